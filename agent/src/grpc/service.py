@@ -1,6 +1,8 @@
 import grpc
 import hello_pb2_grpc as pb2_grpc
 import hello_pb2 as pb2
+from grpc_health.v1 import health, health_pb2, health_pb2_grpc
+from opentelemetry.instrumentation.grpc import GrpcInstrumentorServer
 from concurrent import futures
 from time import sleep
 
@@ -14,10 +16,17 @@ class Greeter(pb2_grpc.GreeterServicer):
     
 
 def serve():
+    # 需在创建 server 前启用，从 metadata 中提取 backend 传来的 traceparent 作为父 span
+    GrpcInstrumentorServer().instrument()
     grpc_server = grpc.server(
         futures.ThreadPoolExecutor(max_workers=10)
     )
     pb2_grpc.add_GreeterServicer_to_server(Greeter(), grpc_server)
+    # 标准 gRPC 健康检查，供 backend 的健康状态接口探测
+    health_servicer = health.HealthServicer()
+    health_pb2_grpc.add_HealthServicer_to_server(health_servicer, grpc_server)
+    health_servicer.set("", health_pb2.HealthCheckResponse.SERVING)
+    health_servicer.set("hello.Greeter", health_pb2.HealthCheckResponse.SERVING)
     grpc_server.add_insecure_port("0.0.0.0:50051")
     print("gRPC server is running on port 50051...")
     grpc_server.start()
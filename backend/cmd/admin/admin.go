@@ -3,7 +3,9 @@ package admin
 import (
 	"fmt"
 	"insight/data"
+	"insight/internal/container"
 	"insight/internal/model"
+	"insight/internal/pkg/cache"
 	log "insight/internal/pkg/logger"
 
 	"github.com/spf13/cobra"
@@ -46,6 +48,8 @@ var (
 		PreRun: func(cmd *cobra.Command, args []string) {
 			// Initialize database connection
 			data.InitData()
+			// The user cache is invalidated after deletion
+			cache.InitCache()
 		},
 		Run: deleteAdmin,
 	}
@@ -101,15 +105,17 @@ func init() {
 
 func createAdmin(cmd *cobra.Command, args []string) {
 	log.Logger.Info("Creating admin user: " + username)
+	deps := container.FromContext(cmd.Context())
+	ctx := deps.Context(cmd.Context())
 
 	// Check database connection
-	if data.MysqlDB == nil {
+	if deps.DB() == nil {
 		log.Logger.Error("Database connection not initialized")
 		return
 	}
 
 	// Test database connection
-	sqlDB, err := data.MysqlDB.DB()
+	sqlDB, err := deps.DB().DB()
 	if err != nil {
 		log.Logger.Error("Failed to get database instance: " + err.Error())
 		return
@@ -122,7 +128,7 @@ func createAdmin(cmd *cobra.Command, args []string) {
 
 	// Check if username already exists
 	adminUser := model.NewAdminUsers()
-	existingUser := adminUser.GetUserInfo(username)
+	existingUser := adminUser.GetUserInfo(ctx, username)
 	if existingUser != nil {
 		log.Logger.Warn("Username already exists: " + username)
 		return
@@ -159,7 +165,7 @@ func createAdmin(cmd *cobra.Command, args []string) {
 	newUser.Password = hashedPassword
 
 	// Use GORM Create but with proper model handling
-	result := data.MysqlDB.Create(newUser)
+	result := model.DB(ctx).Create(newUser)
 	if result.Error != nil {
 		log.Logger.Error("Failed to create admin user: " + result.Error.Error())
 		return
@@ -180,9 +186,10 @@ func createAdmin(cmd *cobra.Command, args []string) {
 func listAdmins(cmd *cobra.Command, args []string) {
 	log.Logger.Info("Admin Users List:")
 	log.Logger.Info("==================")
+	ctx := container.FromContext(cmd.Context()).Context(cmd.Context())
 
 	var users []model.AdminUser
-	result := data.MysqlDB.Find(&users)
+	result := model.DB(ctx).Find(&users)
 	if result.Error != nil {
 		log.Logger.Error("Failed to fetch users: " + result.Error.Error())
 		return
@@ -215,31 +222,38 @@ func listAdmins(cmd *cobra.Command, args []string) {
 
 func deleteAdmin(cmd *cobra.Command, args []string) {
 	log.Logger.Info("Deleting admin user: " + targetUser)
+	deps := container.FromContext(cmd.Context())
+	ctx := deps.Context(cmd.Context())
 
 	// Check if user exists
 	adminUser := model.NewAdminUsers()
-	user := adminUser.GetUserInfo(targetUser)
+	user := adminUser.GetUserInfo(ctx, targetUser)
 	if user == nil {
 		log.Logger.Warn("User not found: " + targetUser)
 		return
 	}
 
 	// Soft delete the user
-	result := data.MysqlDB.Delete(user)
+	result := model.DB(ctx).Delete(user)
 	if result.Error != nil {
 		log.Logger.Error("Failed to delete admin user: " + result.Error.Error())
 		return
 	}
+	// Drop cached state so issued tokens stop working
+	if err := deps.AdminUserService().Invalidate(ctx, user.ID); err != nil {
+		log.Logger.Warn("Failed to invalidate user cache: " + err.Error())
+	}
 
 	log.Logger.Info("User deleted successfully: " + targetUser)
 }
 
 func resetPassword(cmd *cobra.Command, args []string) {
 	log.Logger.Info("Resetting password for user: " + targetUser)
+	ctx := container.FromContext(cmd.Context()).Context(cmd.Context())
 
 	// Check if user exists
 	adminUser := model.NewAdminUsers()
-	user := adminUser.GetUserInfo(targetUser)
+	user := adminUser.GetUserInfo(ctx, targetUser)
 	if user == nil {
 		log.Logger.Warn("User not found: " + targetUser)
 		return
@@ -247,7 +261,7 @@ func resetPassword(cmd *cobra.Command, args []string) {
 
 	// Update password
 	user.Password = newPassword
-	err := user.ChangePassword()
+	err := user.ChangePassword(ctx)
 	if err != nil {
 		log.Logger.Error("Failed to reset password: " + err.Error())
 		return
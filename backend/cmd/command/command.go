@@ -1,13 +1,15 @@
 package command
 
 import (
+	"context"
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
-	"insight/config"
 	"insight/data"
 	"insight/internal/console/demo"
+	"insight/internal/container"
+	"insight/internal/pkg/cache"
 	log "insight/internal/pkg/logger"
 	"insight/internal/routers"
 )
@@ -20,9 +22,12 @@ var (
 		PersistentPreRun: func(cmd *cobra.Command, args []string) {
 			// 初始化数据库
 			data.InitData()
+
+			// 缓存初始化
+			cache.InitCache()
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return run()
+			return run(cmd.Context())
 		},
 	}
 )
@@ -31,11 +36,12 @@ func init() {
 	Cmd.AddCommand(demo.DemoCmd)
 }
 
-func run() error {
+func run(ctx context.Context) error {
 	r := gin.Default()
 
-	config := config.GetConfig()
-	routers.SetupRouter(r)
+	deps := container.FromContext(ctx)
+	config := deps.Config()
+	routers.SetupRouter(r, deps)
 	// 启动HTTP服务器，阻塞等待
 	address := fmt.Sprintf("%s:%d", config.System.Host, config.System.Port)
 	log.Logger.Info("Starting server",
@@ -1,12 +1,21 @@
 package corn
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"insight/data"
+	"insight/internal/container"
+	"insight/internal/pkg/lifecycle"
 	log "insight/internal/pkg/logger"
+	"insight/internal/pkg/metrics"
+	"net"
+	"net/http"
 	"time"
 
 	"github.com/robfig/cron/v3"
 	"github.com/spf13/cobra"
+	"go.uber.org/zap"
 )
 
 var (
@@ -16,26 +25,95 @@ var (
 		Example: "insight cron",
 		PreRun: func(cmd *cobra.Command, args []string) {
 			// 计划任务中使用数据请先初始化数据库连接
-			// data.InitData()
+			data.InitData()
 		},
-		Run: func(cmd *cobra.Command, args []string) {
-			Start()
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return Start(cmd.Context())
 		},
 	}
 )
 
-func Start() {
+// Start 注册计划任务并阻塞运行，收到退出信号后等待执行中的任务结束
+func Start(ctx context.Context) error {
+	deps := container.FromContext(ctx)
 	myLog := myLogger{}
 	crontab := cron.New(cron.WithSeconds(), cron.WithChain(cron.Recover(myLog)))
-	job := cron.NewChain(cron.SkipIfStillRunning(myLog), cron.Recover(myLog)).Then(cron.FuncJob(func() {
+	job := cron.NewChain(cron.SkipIfStillRunning(myLog), cron.Recover(myLog)).Then(cron.FuncJob(metrics.ObserveJob("demo", func() error {
 		fmt.Printf("%s:%s\n", time.Now().Format("2006-01-02 15:04:05"), "This is a scheduled task")
-	}))
+		return nil
+	})))
 	_, err := crontab.AddJob("*/5 * * * * *", job)
 	if err != nil {
 		panic("Error adding job:" + err.Error())
 	}
-	crontab.Start()
-	select {}
+
+	// 定期永久删除回收站中超过保留天数的记录
+	trashConfig := deps.Config().Trash
+	if trashConfig.RetentionDays > 0 {
+		purgeJob := cron.NewChain(cron.SkipIfStillRunning(myLog), cron.Recover(myLog)).Then(cron.FuncJob(metrics.ObserveJob("purge_trash", func() error { return purgeTrash(deps) })))
+		if _, err := crontab.AddJob(trashConfig.PurgeSpec, purgeJob); err != nil {
+			panic("Error adding trash purge job:" + err.Error())
+		}
+	}
+
+	app := lifecycle.New()
+	app.StopTimeout = time.Duration(deps.Config().System.ShutdownTimeout) * time.Second
+	app.Append(lifecycle.Hook{
+		Name:   "database",
+		OnStop: func(context.Context) error { return data.Close() },
+	})
+	app.Append(lifecycle.Hook{
+		Name: "cron",
+		OnStart: func(context.Context) error {
+			crontab.Start()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			select {
+			case <-crontab.Stop().Done():
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		},
+	})
+
+	// cron 没有 HTTP 服务，指标通过单独的端口暴露
+	metricsConfig := deps.Config().Metrics
+	if metricsConfig.Enable && metricsConfig.CronAddr != "" {
+		mux := http.NewServeMux()
+		mux.Handle(metricsConfig.Path, metrics.Handler())
+		srv := &http.Server{Addr: metricsConfig.CronAddr, Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+		app.Append(lifecycle.Hook{
+			Name: "metrics",
+			OnStart: func(context.Context) error {
+				listener, err := net.Listen("tcp", srv.Addr)
+				if err != nil {
+					return err
+				}
+				app.Go("metrics", func() error {
+					if err := srv.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+						return err
+					}
+					return nil
+				})
+				return nil
+			},
+			OnStop: srv.Shutdown,
+		})
+	}
+	return app.Run(ctx)
+}
+
+func purgeTrash(deps *container.Container) error {
+	retention := time.Duration(deps.Config().Trash.RetentionDays) * 24 * time.Hour
+	purged, err := deps.TrashService().PurgeBefore(deps.Context(context.Background()), deps.Clock().Now().Add(-retention))
+	if err != nil {
+		log.Logger.Error("Trash purge failed", zap.Error(err))
+		return err
+	}
+	log.Logger.Info("Trash purged", zap.Any("purged", purged))
+	return nil
 }
 
 type myLogger struct {
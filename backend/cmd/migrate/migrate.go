@@ -1,8 +1,11 @@
 package migrate
 
 import (
+	"fmt"
 	"insight/data"
-	"insight/internal/model"
+	_ "insight/data/migrations"
+	"insight/data/migrator"
+	"insight/internal/container"
 	log "insight/internal/pkg/logger"
 
 	"github.com/spf13/cobra"
@@ -12,35 +15,164 @@ var (
 	Cmd = &cobra.Command{
 		Use:     "migrate",
 		Short:   "Database migration tool",
-		Example: "insight migrate",
+		Example: "insight migrate up",
 		PreRun: func(cmd *cobra.Command, args []string) {
 			// Initialize database connection
 			data.InitData()
 		},
-		Run: func(cmd *cobra.Command, args []string) {
-			migrate()
+		// Run all pending migrations when no subcommand is given
+		RunE: up,
+	}
+
+	upCmd = &cobra.Command{
+		Use:     "up",
+		Short:   "Run pending migrations",
+		Example: "insight migrate up --steps=1 --dry-run",
+		PreRun: func(cmd *cobra.Command, args []string) {
+			// Initialize database connection
+			data.InitData()
+		},
+		RunE: up,
+	}
+
+	downCmd = &cobra.Command{
+		Use:     "down",
+		Short:   "Roll back the latest migrations",
+		Example: "insight migrate down --steps=1",
+		PreRun: func(cmd *cobra.Command, args []string) {
+			// Initialize database connection
+			data.InitData()
 		},
+		RunE: down,
 	}
+
+	redoCmd = &cobra.Command{
+		Use:     "redo",
+		Short:   "Roll back and re-run the latest migrations",
+		Example: "insight migrate redo --steps=1",
+		PreRun: func(cmd *cobra.Command, args []string) {
+			// Initialize database connection
+			data.InitData()
+		},
+		RunE: redo,
+	}
+
+	statusCmd = &cobra.Command{
+		Use:   "status",
+		Short: "Show migration status",
+		PreRun: func(cmd *cobra.Command, args []string) {
+			// Initialize database connection
+			data.InitData()
+		},
+		RunE: status,
+	}
+
+	createCmd = &cobra.Command{
+		Use:     "create <name>",
+		Short:   "Create a new migration file",
+		Example: "insight migrate create create_roles",
+		Args:    cobra.ExactArgs(1),
+		RunE:    create,
+	}
+
+	// Flags
+	steps  int
+	dryRun bool
+	dir    string
 )
 
-func migrate() {
-	log.Logger.Info("Starting database migration...")
+func init() {
+	// Add subcommands
+	Cmd.AddCommand(upCmd)
+	Cmd.AddCommand(downCmd)
+	Cmd.AddCommand(redoCmd)
+	Cmd.AddCommand(statusCmd)
+	Cmd.AddCommand(createCmd)
 
-	// Auto migrate table schema
-	err := data.MysqlDB.AutoMigrate(
-		&model.AdminUser{},
-		&model.Permission{},
-	)
+	for _, c := range []*cobra.Command{upCmd, downCmd, redoCmd} {
+		c.Flags().IntVarP(&steps, "steps", "s", 0, "Number of migrations to run (up: all, down/redo: 1)")
+		c.Flags().BoolVar(&dryRun, "dry-run", false, "Print the SQL without executing it")
+	}
+
+	createCmd.Flags().StringVarP(&dir, "dir", "d", "data/migrations", "Directory of migration files")
+}
+
+func newMigrator(cmd *cobra.Command) *migrator.Migrator {
+	m := migrator.New(container.FromContext(cmd.Context()).DB())
+	m.DryRun = dryRun
+	return m
+}
+
+func up(cmd *cobra.Command, args []string) error {
+	m := newMigrator(cmd)
+	done, err := m.Up(steps)
+	return report(m, "Migrated", done, err)
+}
+
+func down(cmd *cobra.Command, args []string) error {
+	m := newMigrator(cmd)
+	done, err := m.Down(steps)
+	return report(m, "Rolled back", done, err)
+}
+
+func redo(cmd *cobra.Command, args []string) error {
+	m := newMigrator(cmd)
+	done, err := m.Redo(steps)
+	return report(m, "Redone", done, err)
+}
+
+// report prints the dry-run SQL and the migrations that were run. The error is
+// returned so the process exits non-zero, e.g. when running as an init container.
+func report(m *migrator.Migrator, action string, done []*migrator.Migration, err error) error {
+	if m.DryRun {
+		for _, statement := range m.Statements {
+			fmt.Println(statement + ";")
+		}
+	}
+
+	for _, migration := range done {
+		log.Logger.Info(action + ": " + migration.ID())
+	}
 
 	if err != nil {
 		log.Logger.Error("Database migration failed: " + err.Error())
-		return
+		return err
+	}
+	if len(done) == 0 {
+		log.Logger.Info("Nothing to migrate")
+		return nil
 	}
+	log.Logger.Info("Database migration completed")
+	return nil
+}
 
-	log.Logger.Info("Database migration completed!")
-	log.Logger.Info("Created/Updated tables:")
-	log.Logger.Info("  - a_admin_user (Admin user table)")
-	log.Logger.Info("  - permissions (Permission table)")
+func status(cmd *cobra.Command, args []string) error {
+	statuses, err := migrator.New(container.FromContext(cmd.Context()).DB()).Status()
+	if err != nil {
+		log.Logger.Error("Failed to fetch migration status: " + err.Error())
+		return err
+	}
 
-	log.Logger.Info("Database migration completed")
+	fmt.Printf("%-16s %-40s %-8s %-20s\n", "Version", "Name", "Batch", "Applied At")
+	fmt.Println("--------------------------------------------------------------------------------------")
+
+	for _, s := range statuses {
+		batch, appliedAt := "-", "pending"
+		if s.Applied != nil {
+			batch = fmt.Sprintf("%d", s.Applied.Batch)
+			appliedAt = s.Applied.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		fmt.Printf("%-16s %-40s %-8s %-20s\n", s.Migration.Version, s.Migration.Name, batch, appliedAt)
+	}
+	return nil
+}
+
+func create(cmd *cobra.Command, args []string) error {
+	path, err := migrator.Create(dir, args[0])
+	if err != nil {
+		log.Logger.Error("Failed to create migration: " + err.Error())
+		return err
+	}
+	log.Logger.Info("Created migration: " + path)
+	return nil
 }
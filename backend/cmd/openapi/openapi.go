@@ -0,0 +1,57 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"insight/internal/container"
+	"insight/internal/routers"
+	"os"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/cobra"
+)
+
+var (
+	Cmd = &cobra.Command{
+		Use:   "openapi",
+		Short: "OpenAPI document of the HTTP API",
+	}
+
+	exportCmd = &cobra.Command{
+		Use:     "export",
+		Short:   "Export the OpenAPI document for client code generation",
+		Example: "insight openapi export -o openapi.json",
+		RunE:    export,
+	}
+
+	// Flags
+	output string
+)
+
+func init() {
+	Cmd.AddCommand(exportCmd)
+
+	exportCmd.Flags().StringVarP(&output, "output", "o", "", "Output file (default stdout)")
+}
+
+func export(cmd *cobra.Command, args []string) error {
+	// The debug mode prints registered routes to stdout
+	gin.SetMode(gin.ReleaseMode)
+	r := gin.New()
+	routers.SetupRouter(r, container.FromContext(cmd.Context()))
+
+	doc, err := json.MarshalIndent(routers.OpenAPI(r.Routes()), "", "  ")
+	if err != nil {
+		return err
+	}
+	doc = append(doc, '\n')
+	if output == "" {
+		_, err = os.Stdout.Write(doc)
+		return err
+	}
+	if err := os.WriteFile(output, doc, 0o644); err != nil {
+		return err
+	}
+	fmt.Printf("OpenAPI document written to %s\n", output)
+	return nil
+}
@@ -1,13 +1,20 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"insight/cmd/admin"
+	"insight/cmd/cert"
 	"insight/cmd/command"
 	corn "insight/cmd/cron"
 	"insight/cmd/migrate"
+	"insight/cmd/openapi"
+	"insight/cmd/routes"
+	"insight/cmd/seed"
 	"insight/cmd/server"
+	"insight/cmd/trash"
 	"insight/cmd/version"
+	"insight/internal/container"
 	"insight/internal/global"
 	log "insight/internal/pkg/logger"
 	"os"
@@ -44,10 +51,18 @@ func init() {
 	rootCmd.AddCommand(corn.Cmd)
 	rootCmd.AddCommand(migrate.Cmd)
 	rootCmd.AddCommand(admin.Cmd)
+	rootCmd.AddCommand(seed.Cmd)
+	rootCmd.AddCommand(trash.Cmd)
+	rootCmd.AddCommand(cert.Cmd)
+	rootCmd.AddCommand(openapi.Cmd)
+	rootCmd.AddCommand(routes.Cmd)
 }
 
+// Execute runs the root command. Subcommands resolve their dependencies from the
+// application container carried by cmd.Context().
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
+	ctx := container.NewContext(context.Background(), container.New())
+	if err := rootCmd.ExecuteContext(ctx); err != nil {
 		fmt.Println(err)
 		os.Exit(-1)
 	}
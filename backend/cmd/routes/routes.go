@@ -0,0 +1,80 @@
+package routes
+
+import (
+	"errors"
+	"fmt"
+	"insight/data"
+	"insight/internal/container"
+	"insight/internal/pkg/deprecation"
+	"insight/internal/routers"
+	"os"
+	"text/tabwriter"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/cobra"
+)
+
+var (
+	Cmd = &cobra.Command{
+		Use:   "routes",
+		Short: "Inspect HTTP routes",
+	}
+
+	deprecatedCmd = &cobra.Command{
+		Use:     "deprecated",
+		Short:   "List deprecated routes and their recent traffic",
+		Example: "insight routes deprecated --days=30",
+		PreRun: func(cmd *cobra.Command, args []string) {
+			// Initialize database connection
+			data.InitData()
+		},
+		RunE: deprecated,
+	}
+
+	// Flags
+	days int
+)
+
+func init() {
+	Cmd.AddCommand(deprecatedCmd)
+
+	deprecatedCmd.Flags().IntVar(&days, "days", 7, "Number of days of traffic to include, counting today")
+}
+
+func deprecated(cmd *cobra.Command, args []string) error {
+	if days <= 0 {
+		return errors.New("--days must be positive")
+	}
+	deps := container.FromContext(cmd.Context())
+	// Routes are marked deprecated while they are registered
+	gin.SetMode(gin.ReleaseMode)
+	routers.SetupRouter(gin.New(), deps)
+
+	reports, err := deps.RouteHitService().Report(deps.Context(cmd.Context()), days)
+	if err != nil {
+		return err
+	}
+	if len(reports) == 0 {
+		fmt.Println("No deprecated routes.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "METHOD\tROUTE\tSINCE\tSUNSET\tHITS (%dd)\tLAST SEEN\tSUCCESSOR\n", days)
+	for _, r := range reports {
+		sunset, lastSeen := "-", "-"
+		if !r.Sunset.IsZero() {
+			sunset = r.Sunset.Format(deprecation.DayLayout)
+		}
+		if !r.LastSeen.IsZero() {
+			lastSeen = r.LastSeen.Format("2006-01-02 15:04:05")
+		}
+		successor := r.Successor
+		if successor == "" {
+			successor = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%s\t%s\n",
+			r.Method, r.Path, r.Since.Format(deprecation.DayLayout), sunset, r.Hits, lastSeen, successor)
+	}
+	return w.Flush()
+}
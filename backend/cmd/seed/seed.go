@@ -0,0 +1,77 @@
+package seed
+
+import (
+	"fmt"
+	"insight/data"
+	"insight/data/seeder"
+	"insight/data/seeds"
+	"insight/internal/container"
+	log "insight/internal/pkg/logger"
+	"io/fs"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	Cmd = &cobra.Command{
+		Use:     "seed",
+		Short:   "Load seed data into the database",
+		Example: "insight seed --env=test --truncate",
+		PreRun: func(cmd *cobra.Command, args []string) {
+			// Initialize database connection
+			data.InitData()
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return seed(container.FromContext(cmd.Context()))
+		},
+	}
+
+	// Flags
+	env      string
+	dir      string
+	truncate bool
+	force    bool
+)
+
+func init() {
+	Cmd.Flags().StringVarP(&env, "env", "e", "dev", "Seed set to load on top of base: dev, demo, test")
+	Cmd.Flags().StringVarP(&dir, "dir", "d", "", "Load seed sets from this directory instead of the built-in ones")
+	Cmd.Flags().BoolVar(&truncate, "truncate", false, "Empty the seeded tables first (test seed set only)")
+	Cmd.Flags().BoolVar(&force, "force", false, "Allow --truncate for seed sets other than test")
+}
+
+func seed(deps *container.Container) error {
+	var fsys fs.FS = seeds.FS
+	if dir != "" {
+		fsys = os.DirFS(dir)
+	}
+	s := seeder.New(deps.DB(), fsys)
+
+	if truncate {
+		if env != "test" && !force {
+			return fmt.Errorf("refusing to truncate with seed set %q, use --force to override", env)
+		}
+		log.Logger.Warn("Truncating seeded tables")
+		if err := s.Truncate(); err != nil {
+			log.Logger.Error("Failed to truncate tables: " + err.Error())
+			return err
+		}
+	}
+
+	log.Logger.Info("Seeding database with seed set: " + env)
+	result, err := s.Seed(env)
+	if err != nil {
+		log.Logger.Error("Database seeding failed: " + err.Error())
+		return err
+	}
+
+	fmt.Printf("%-15s %-8s %-8s\n", "Kind", "Created", "Updated")
+	fmt.Println("---------------------------------")
+	for _, kind := range result.Kinds() {
+		fmt.Printf("%-15s %-8d %-8d\n", kind, result[kind].Created, result[kind].Updated)
+	}
+
+	log.Logger.Info("Database seeding completed")
+	return nil
+}
@@ -0,0 +1,54 @@
+package server
+
+import (
+	"context"
+	"insight/internal/container"
+	"insight/internal/pkg/deprecation"
+	"insight/internal/pkg/lifecycle"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// routeHitsFlushInterval is how often deprecated route traffic is written to the database.
+const routeHitsFlushInterval = time.Minute
+
+// routeHitsHook periodically saves deprecated route traffic for `insight routes deprecated`,
+// with a final flush on stop. It must stop after the HTTP server and before the database.
+func routeHitsHook(deps *container.Container) lifecycle.Hook {
+	save := func(ctx context.Context, counts map[deprecation.Hit]int64) error {
+		return deps.RouteHitService().Save(deps.Context(ctx), counts)
+	}
+	var (
+		cancel context.CancelFunc
+		done   = make(chan struct{})
+	)
+	return lifecycle.Hook{
+		Name: "route_hits",
+		OnStart: func(context.Context) error {
+			var ctx context.Context
+			ctx, cancel = context.WithCancel(context.Background())
+			go func() {
+				defer close(done)
+				ticker := time.NewTicker(routeHitsFlushInterval)
+				defer ticker.Stop()
+				for {
+					select {
+					case <-ctx.Done():
+						return
+					case <-ticker.C:
+						if err := deprecation.Default().Flush(ctx, save); err != nil {
+							deps.Logger().Warn("Failed to save deprecated route hits", zap.Error(err))
+						}
+					}
+				}
+			}()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			cancel()
+			<-done
+			return deprecation.Default().Flush(ctx, save)
+		},
+	}
+}
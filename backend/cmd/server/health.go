@@ -0,0 +1,46 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"insight/data"
+	_ "insight/data/migrations"
+	"insight/data/migrator"
+	"insight/internal/container"
+	"insight/internal/pkg/agent"
+	"insight/internal/pkg/health"
+	"insight/internal/pkg/lifecycle"
+	"time"
+)
+
+// registerHealthChecks registers the dependency checks behind /readyz and the admin health view.
+// Critical checks gate readiness; the agent and disk checks only show up in the detailed status.
+func registerHealthChecks(app *lifecycle.Lifecycle, deps *container.Container) {
+	cfg := deps.Config().Health
+	health.Default().Timeout = time.Duration(cfg.Timeout) * time.Second
+
+	health.Register(health.Check{Name: "lifecycle", Critical: true, Fn: health.Ready(app.Ready)})
+	health.Register(health.Check{Name: "cache", Critical: true, Fn: deps.Cache().Ping})
+	if deps.DB() != nil {
+		health.Register(health.Check{Name: "database", Critical: true, Fn: data.Ping})
+		health.Register(health.Check{Name: "migrations", Critical: true, Fn: func(context.Context) error {
+			return migrationsUpToDate(deps)
+		}})
+	}
+	health.Register(health.Check{Name: "agent", Fn: agent.Health})
+	health.Register(health.Check{
+		Name: "disk",
+		Fn:   health.DiskSpace(cfg.DiskPath, uint64(cfg.MinFreeDisk)<<20),
+	})
+}
+
+func migrationsUpToDate(deps *container.Container) error {
+	pending, err := migrator.New(deps.DB()).Pending()
+	if err != nil {
+		return err
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("%d pending migrations, next %s", len(pending), pending[0].ID())
+	}
+	return nil
+}
@@ -1,13 +1,24 @@
 package server
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"insight/config"
 	"insight/data"
+	"insight/internal/container"
 	"insight/internal/middleware"
-	log "insight/internal/pkg/logger"
+	"insight/internal/pkg/agent"
+	"insight/internal/pkg/cache"
+	"insight/internal/pkg/certs"
+	"insight/internal/pkg/diagnostics"
+	"insight/internal/pkg/lifecycle"
+	"insight/internal/pkg/tracing"
 	"insight/internal/routers"
 	"insight/internal/validator"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -24,32 +35,150 @@ var (
 			// 数据库初始化
 			data.InitData()
 
+			// 缓存初始化
+			cache.InitCache()
+
 			// 初始化验证器
 			validator.InitValidatorTrans("zh")
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return run()
+			return run(cmd.Context())
 		},
 	}
 )
 
-func run() error {
-	r := gin.Default()
+func run(ctx context.Context) error {
+	deps := container.FromContext(ctx)
+	cfg := deps.Config().System
+	if !cfg.Debug {
+		gin.SetMode(gin.ReleaseMode)
+	}
+	if err := middleware.ValidateCors(deps.Config().Cors); err != nil {
+		return err
+	}
+	r, err := routers.NewEngine(deps)
+	if err != nil {
+		return err
+	}
+	tlsConfig := deps.Config().TLS
+	tracingConfig := deps.Config().Tracing
 
-	// 配置CORS中间件
-	r.Use(middleware.CorsHandler())
+	address := fmt.Sprintf("%s:%d", cfg.Host, cfg.Port)
+	srv := &http.Server{
+		Addr:              address,
+		Handler:           r,
+		ReadTimeout:       time.Duration(cfg.ReadTimeout) * time.Second,
+		ReadHeaderTimeout: time.Duration(cfg.ReadHeaderTimeout) * time.Second,
+		WriteTimeout:      time.Duration(cfg.WriteTimeout) * time.Second,
+		IdleTimeout:       time.Duration(cfg.IdleTimeout) * time.Second,
+	}
 
-	config := config.GetConfig()
-	routers.SetupRouter(r)
+	app := lifecycle.New()
+	app.StopTimeout = time.Duration(cfg.ShutdownTimeout) * time.Second
+	app.DrainDelay = time.Duration(cfg.DrainDelay) * time.Second
+	registerHealthChecks(app, deps)
 
-	// 启动HTTP服务器，阻塞等待
-	address := fmt.Sprintf("%s:%d", config.System.Host, config.System.Port)
-	log.Logger.Info("Starting server",
-		zap.String("address", address),
-	)
+	// 钩子按顺序启动、逆序停止：HTTP 最先停止，日志最后刷新
+	app.Append(lifecycle.Hook{
+		Name:   "logger",
+		OnStop: func(context.Context) error { return syncLogger(deps.Logger()) },
+	})
+	var shutdownTracing func(context.Context) error
+	app.Append(lifecycle.Hook{
+		Name: "tracing",
+		OnStart: func(ctx context.Context) (err error) {
+			shutdownTracing, err = tracing.Init(ctx, tracingConfig)
+			return err
+		},
+		// 刷新尚未导出的 span
+		OnStop: func(ctx context.Context) error { return shutdownTracing(ctx) },
+	})
+	app.Append(lifecycle.Hook{
+		Name:   "database",
+		OnStop: func(context.Context) error { return data.Close() },
+	})
+	if deps.DB() != nil {
+		app.Append(routeHitsHook(deps))
+	}
+	app.Append(lifecycle.Hook{
+		Name:   "cache",
+		OnStop: func(context.Context) error { return deps.Cache().Close() },
+	})
+	app.Append(lifecycle.Hook{
+		Name:    "agent",
+		OnStart: func(context.Context) error { return agent.Init() },
+		OnStop:  func(context.Context) error { return agent.Close() },
+	})
+	if debugConfig := deps.Config().Debug; debugConfig.Enable && debugConfig.Addr != "" {
+		hook, err := debugHook(app, deps, debugConfig.Addr)
+		if err != nil {
+			return err
+		}
+		app.Append(hook)
+	}
+	app.Append(lifecycle.Hook{
+		Name: "http",
+		OnStart: func(ctx context.Context) error {
+			// 同步监听，端口占用等错误在启动阶段返回
+			listener, err := net.Listen("tcp", address)
+			if err != nil {
+				return err
+			}
+			serve := srv.Serve
+			if tlsConfig.Enable {
+				if srv.TLSConfig, err = certs.ServerConfig(tlsConfig); err != nil {
+					_ = listener.Close()
+					return err
+				}
+				// 证书由 TLSConfig.GetCertificate 提供，ServeTLS 同时启用 HTTP/2
+				serve = func(l net.Listener) error { return srv.ServeTLS(l, "", "") }
+			}
+			deps.Logger().Info("Starting server", zap.String("address", address), zap.Bool("tls", tlsConfig.Enable))
+			app.Go("http", func() error {
+				if err := serve(listener); !errors.Is(err, http.ErrServerClosed) {
+					return err
+				}
+				return nil
+			})
+			return nil
+		},
+		// 停止接收新连接，等待处理中的请求完成
+		OnStop: srv.Shutdown,
+	})
 
-	err := r.Run(address)
-	if err != nil {
+	return app.Run(ctx)
+}
+
+// debugHook serves the diagnostics on a separate listener. The routes are not
+// authenticated there, so the address must be a loopback one.
+func debugHook(app *lifecycle.Lifecycle, deps *container.Container, addr string) (lifecycle.Hook, error) {
+	if err := diagnostics.CheckLoopback(addr); err != nil {
+		return lifecycle.Hook{}, err
+	}
+	srv := &http.Server{Addr: addr, Handler: routers.NewDebugEngine(deps), ReadHeaderTimeout: 5 * time.Second}
+	return lifecycle.Hook{
+		Name: "debug",
+		OnStart: func(context.Context) error {
+			listener, err := net.Listen("tcp", srv.Addr)
+			if err != nil {
+				return err
+			}
+			deps.Logger().Info("Starting debug server", zap.String("address", addr))
+			app.Go("debug", func() error {
+				if err := srv.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+					return err
+				}
+				return nil
+			})
+			return nil
+		},
+		OnStop: srv.Shutdown,
+	}, nil
+}
+
+// syncLogger flushes buffered logs; syncing stdout/stderr fails with EINVAL on some platforms and is ignored.
+func syncLogger(logger *zap.Logger) error {
+	if err := logger.Sync(); err != nil && !errors.Is(err, syscall.EINVAL) && !errors.Is(err, syscall.ENOTTY) {
 		return err
 	}
 	return nil
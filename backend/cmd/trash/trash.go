@@ -0,0 +1,86 @@
+package trash
+
+import (
+	"fmt"
+	"insight/data"
+	"insight/internal/container"
+	"insight/internal/model"
+	log "insight/internal/pkg/logger"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	Cmd = &cobra.Command{
+		Use:   "trash",
+		Short: "Manage soft-deleted records",
+	}
+
+	purgeCmd = &cobra.Command{
+		Use:     "purge",
+		Short:   "Permanently delete records that were soft-deleted long ago",
+		Example: "insight trash purge --older-than=30d --resource=roles",
+		PreRun: func(cmd *cobra.Command, args []string) {
+			// Initialize database connection
+			data.InitData()
+		},
+		RunE: purge,
+	}
+
+	// Flags
+	olderThan string
+	resources []string
+)
+
+func init() {
+	Cmd.AddCommand(purgeCmd)
+
+	purgeCmd.Flags().StringVar(&olderThan, "older-than", "", "Minimum age of deleted records, e.g. 30d or 12h (default trash.retention_days)")
+	purgeCmd.Flags().StringSliceVarP(&resources, "resource", "r", nil, "Resources to purge: "+strings.Join(model.TrashResources(), ", ")+" (default all)")
+}
+
+func purge(cmd *cobra.Command, args []string) error {
+	deps := container.FromContext(cmd.Context())
+	age := time.Duration(deps.Config().Trash.RetentionDays) * 24 * time.Hour
+	if olderThan != "" {
+		var err error
+		if age, err = parseAge(olderThan); err != nil {
+			return err
+		}
+	}
+	if age <= 0 {
+		return fmt.Errorf("--older-than must be positive")
+	}
+
+	before := deps.Clock().Now().Add(-age)
+	log.Logger.Info("Purging records deleted before " + before.Format(time.DateTime))
+	purged, err := deps.TrashService().PurgeBefore(deps.Context(cmd.Context()), before, resources...)
+
+	fmt.Printf("%-15s %-8s\n", "Resource", "Purged")
+	fmt.Println("------------------------")
+	for _, resource := range model.TrashResources() {
+		if count, ok := purged[resource]; ok {
+			fmt.Printf("%-15s %-8d\n", resource, count)
+		}
+	}
+	if err != nil {
+		log.Logger.Error("Failed to purge trash: " + err.Error())
+		return err
+	}
+	return nil
+}
+
+// parseAge parses a duration that additionally accepts a day suffix, e.g. 30d.
+func parseAge(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("invalid age %q", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(s)
+}
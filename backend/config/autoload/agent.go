@@ -0,0 +1,6 @@
+package autoload
+
+type AgentConfig struct {
+	Addr    string `mapstructure:"addr"`    // Python agent 的 gRPC 地址
+	Timeout int    `mapstructure:"timeout"` // 单次调用超时时间(秒)
+}
@@ -0,0 +1,21 @@
+package autoload
+
+type CacheConfig struct {
+	Driver     string            `mapstructure:"driver"`      // memory, redis
+	Prefix     string            `mapstructure:"prefix"`      // 缓存键前缀
+	DefaultTTL int               `mapstructure:"default_ttl"` // 默认过期时间(秒)
+	Memory     MemoryCacheConfig `mapstructure:"memory"`
+	Redis      RedisConfig       `mapstructure:"redis"`
+}
+
+type MemoryCacheConfig struct {
+	Capacity int `mapstructure:"capacity"` // 最大缓存条目数，超出后淘汰最久未使用的条目
+}
+
+type RedisConfig struct {
+	Addr     string `mapstructure:"addr"`
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+	DB       int    `mapstructure:"db"`
+	PoolSize int    `mapstructure:"pool_size"`
+}
@@ -0,0 +1,10 @@
+package autoload
+
+type CorsConfig struct {
+	AllowOrigins     []string `mapstructure:"allow_origins"` // 允许的来源，支持 https://*.example.com 形式的通配符，修改后无需重启
+	AllowMethods     []string `mapstructure:"allow_methods"`
+	AllowHeaders     []string `mapstructure:"allow_headers"`
+	ExposeHeaders    []string `mapstructure:"expose_headers"` // 浏览器可读取的响应头
+	AllowCredentials bool     `mapstructure:"allow_credentials"`
+	MaxAge           int      `mapstructure:"max_age"` // 预检请求缓存时间(秒)
+}
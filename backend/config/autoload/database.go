@@ -0,0 +1,34 @@
+package autoload
+
+type DatabaseConfig struct {
+	Driver       string `mapstructure:"driver"` // mysql, postgres, sqlite
+	Host         string `mapstructure:"host"`
+	Port         int    `mapstructure:"port"`
+	Username     string `mapstructure:"username"`
+	Password     string `mapstructure:"password"`
+	Database     string `mapstructure:"database"`
+	Charset      string `mapstructure:"charset"`   // mysql
+	SSLMode      string `mapstructure:"ssl_mode"`  // postgres
+	TimeZone     string `mapstructure:"time_zone"` // postgres
+	Path         string `mapstructure:"path"`      // sqlite, ":memory:" 表示内存数据库
+	PrintSql     bool   `mapstructure:"print_sql"`
+	LogLevel     string `mapstructure:"log_level"`
+	TablePrefix  string `mapstructure:"table_prefix"`
+	MaxIdleConns int    `mapstructure:"max_idle_conns"`
+	MaxOpenConns int    `mapstructure:"max_open_conns"`
+	MaxLifetime  int    `mapstructure:"max_life_time"`
+	Enable       bool   `mapstructure:"enable"`
+
+	Replicas            []ReplicaConfig `mapstructure:"replicas"`              // 从库列表，未设置的字段沿用主库配置
+	ReplicaPolicy       string          `mapstructure:"replica_policy"`        // random, round_robin, least_conn
+	HealthCheckInterval int             `mapstructure:"health_check_interval"` // 从库健康检查间隔(秒)
+}
+
+type ReplicaConfig struct {
+	Host     string `mapstructure:"host"`
+	Port     int    `mapstructure:"port"`
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+	Database string `mapstructure:"database"`
+	Path     string `mapstructure:"path"` // sqlite
+}
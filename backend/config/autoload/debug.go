@@ -0,0 +1,6 @@
+package autoload
+
+type DebugConfig struct {
+	Enable bool   `mapstructure:"enable"` // 是否提供 pprof 与运行时诊断接口
+	Addr   string `mapstructure:"addr"`   // 单独监听的本机地址，如 127.0.0.1:6060；为空时挂载到主服务 /admin/debug 并要求超级管理员登录
+}
@@ -0,0 +1,7 @@
+package autoload
+
+type HealthConfig struct {
+	Timeout     int    `mapstructure:"timeout"`       // 单项检查超时(秒)
+	DiskPath    string `mapstructure:"disk_path"`     // 检查可用空间的目录
+	MinFreeDisk int    `mapstructure:"min_free_disk"` // 最小可用空间(MB)
+}
@@ -5,6 +5,13 @@ type LoggerConfig struct {
 	DefaultDivision string       `mapstructure:"default_division"`
 	DivisionTime    DivisionTime `mapstructure:"division_time"`
 	DivisionSize    DivisionSize `mapstructure:"division_size"`
+	Access          AccessLog    `mapstructure:"access"`
+}
+
+type AccessLog struct {
+	Enable     bool     `mapstructure:"enable"`
+	SampleRate float64  `mapstructure:"sample_rate"` // 成功请求的采样比例 0-1，4xx/5xx 始终记录
+	SkipPaths  []string `mapstructure:"skip_paths"`  // 不记录的请求路径，如探针与指标接口
 }
 
 type DivisionTime struct {
@@ -0,0 +1,7 @@
+package autoload
+
+type MetricsConfig struct {
+	Enable   bool   `mapstructure:"enable"`    // 是否暴露 Prometheus 指标
+	Path     string `mapstructure:"path"`      // 指标路径
+	CronAddr string `mapstructure:"cron_addr"` // cron 进程单独监听的指标地址，为空时不暴露
+}
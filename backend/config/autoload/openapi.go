@@ -0,0 +1,5 @@
+package autoload
+
+type OpenAPIConfig struct {
+	Enable bool `mapstructure:"enable"` // 是否提供 /api/openapi.json 与 /api/docs/
+}
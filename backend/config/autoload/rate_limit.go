@@ -0,0 +1,15 @@
+package autoload
+
+type RateLimitConfig struct {
+	Enable   bool                       `mapstructure:"enable"`
+	Backend  string                     `mapstructure:"backend"` // memory: 进程内令牌桶, cache: 基于缓存的滑动窗口
+	Policies map[string]RateLimitPolicy `mapstructure:"policies"`
+	APIKeys  []string                   `mapstructure:"api_keys"` // 按 api_key 计数时识别的 X-API-Key，不在其中的按 ip 计数
+}
+
+type RateLimitPolicy struct {
+	Limit  int    `mapstructure:"limit"`  // 每个周期允许的请求数，0 表示不限制
+	Period int    `mapstructure:"period"` // 周期(秒)
+	Burst  int    `mapstructure:"burst"`  // 令牌桶容量，默认等于 limit (仅 memory)
+	By     string `mapstructure:"by"`     // 计数维度: ip, user, client(客户端证书身份), api_key，无法识别时按 ip 计数
+}
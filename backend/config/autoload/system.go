@@ -1,8 +1,17 @@
 package autoload
 
 type SystemConfig struct {
-	Host     string `mapstructure:"host"`
-	Port     int    `mapstructure:"port"`
-	Language string `mapstructure:"language"`
-	Debug    bool   `mapstructure:"debug"`
+	Host              string `mapstructure:"host"`
+	Port              int    `mapstructure:"port"`
+	Language          string `mapstructure:"language"`
+	Debug             bool   `mapstructure:"debug"`
+	Env               string `mapstructure:"env"`                 // 运行环境: dev, test, production，决定部分配置的默认值
+	ReadTimeout       int    `mapstructure:"read_timeout"`        // 读取请求的超时时间(秒)
+	ReadHeaderTimeout int    `mapstructure:"read_header_timeout"` // 读取请求头的超时时间(秒)
+	WriteTimeout      int    `mapstructure:"write_timeout"`       // 写响应的超时时间(秒)
+	IdleTimeout       int    `mapstructure:"idle_timeout"`        // keep-alive 连接的空闲超时时间(秒)
+	ShutdownTimeout   int    `mapstructure:"shutdown_timeout"`    // 优雅停止的最长等待时间(秒)
+	DrainDelay        int    `mapstructure:"drain_delay"`         // 收到退出信号后就绪状态置为 false，等待多少秒再停止接收请求
+	// 受信任的反向代理 IP 或 CIDR，只有来自这些地址的请求才读取 X-Forwarded-For，默认不信任任何代理
+	TrustedProxies []string `mapstructure:"trusted_proxies"`
 }
@@ -0,0 +1,19 @@
+package autoload
+
+type TLSConfig struct {
+	Enable           bool              `mapstructure:"enable"`
+	CertFile         string            `mapstructure:"cert_file"`
+	KeyFile          string            `mapstructure:"key_file"`
+	ReloadInterval   int               `mapstructure:"reload_interval"`   // 检查证书文件变更的间隔(秒)，替换文件后无需重启
+	MinVersion       string            `mapstructure:"min_version"`       // 1.2, 1.3
+	ClientCAFile     string            `mapstructure:"client_ca_file"`    // 校验客户端证书的 CA
+	ClientAuth       string            `mapstructure:"client_auth"`       // none, optional(提供时校验), require
+	ClientIdentities map[string]string `mapstructure:"client_identities"` // 客户端证书 CN 到身份的映射，为空时身份即 CN
+	HSTS             HSTSConfig        `mapstructure:"hsts"`
+}
+
+type HSTSConfig struct {
+	MaxAge            int  `mapstructure:"max_age"` // 秒，0 表示不发送 Strict-Transport-Security
+	IncludeSubdomains bool `mapstructure:"include_subdomains"`
+	Preload           bool `mapstructure:"preload"`
+}
@@ -0,0 +1,11 @@
+package autoload
+
+type TracingConfig struct {
+	Enable      bool    `mapstructure:"enable"`
+	ServiceName string  `mapstructure:"service_name"`
+	Exporter    string  `mapstructure:"exporter"`     // otlp, stdout, file
+	Endpoint    string  `mapstructure:"endpoint"`     // OTLP gRPC 地址 (仅 otlp)
+	Insecure    bool    `mapstructure:"insecure"`     // OTLP 不使用 TLS (仅 otlp)
+	FilePath    string  `mapstructure:"file_path"`    // 输出文件 (仅 file)
+	SampleRatio float64 `mapstructure:"sample_ratio"` // 采样比例 0-1，上游已采样的请求始终采样
+}
@@ -0,0 +1,6 @@
+package autoload
+
+type TrashConfig struct {
+	RetentionDays int    `mapstructure:"retention_days"` // 回收站保留天数，超过后由定时任务永久删除，0 表示不自动清理
+	PurgeSpec     string `mapstructure:"purge_spec"`     // 自动清理的 cron 表达式(含秒)
+}
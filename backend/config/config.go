@@ -16,10 +16,21 @@ var (
 )
 
 type Config struct {
-	MySQL  autoload.MySQLConfig  `mapstructure:"mysql"`
-	System autoload.SystemConfig `mapstructure:"system"`
-	Logger autoload.LoggerConfig `mapstructure:"logger"`
-	Jwt    autoload.JwtConfig    `mapstructure:"jwt"`
+	Database  autoload.DatabaseConfig  `mapstructure:"database"`
+	System    autoload.SystemConfig    `mapstructure:"system"`
+	Logger    autoload.LoggerConfig    `mapstructure:"logger"`
+	Jwt       autoload.JwtConfig       `mapstructure:"jwt"`
+	Cache     autoload.CacheConfig     `mapstructure:"cache"`
+	Trash     autoload.TrashConfig     `mapstructure:"trash"`
+	Agent     autoload.AgentConfig     `mapstructure:"agent"`
+	Health    autoload.HealthConfig    `mapstructure:"health"`
+	Metrics   autoload.MetricsConfig   `mapstructure:"metrics"`
+	Tracing   autoload.TracingConfig   `mapstructure:"tracing"`
+	RateLimit autoload.RateLimitConfig `mapstructure:"rate_limit"`
+	Cors      autoload.CorsConfig      `mapstructure:"cors"`
+	TLS       autoload.TLSConfig       `mapstructure:"tls"`
+	OpenAPI   autoload.OpenAPIConfig   `mapstructure:"openapi"`
+	Debug     autoload.DebugConfig     `mapstructure:"debug"`
 }
 
 // LoadConfig loads application configuration from a file and returns a populated Config.
@@ -35,6 +46,7 @@ func LoadConfig() *Config {
 	log.Println("Loading config from ", configFile)
 
 	viper.SetConfigFile(configFile)
+	setDefaults(viper.GetViper())
 	if err := viper.ReadInConfig(); err != nil {
 		log.Fatalf("Error reading config file, %s", err)
 	}
@@ -47,9 +59,87 @@ func LoadConfig() *Config {
 	return &config
 }
 
+// Defaults returns the configuration made of the built-in defaults only, without
+// reading any file. Tests start from it and override what they need.
+func Defaults() *Config {
+	v := viper.New()
+	setDefaults(v)
+	var config Config
+	if err := v.Unmarshal(&config); err != nil {
+		log.Fatalf("Error unmarshalling default config, %s", err)
+	}
+	return &config
+}
+
+func setDefaults(v *viper.Viper) {
+	v.SetDefault("database.driver", "mysql")
+	v.SetDefault("system.env", "production")
+	v.SetDefault("cache.driver", "memory")
+	v.SetDefault("logger.access.enable", true)
+	v.SetDefault("logger.access.sample_rate", 1.0)
+	v.SetDefault("logger.access.skip_paths", []string{"/healthz", "/readyz", "/metrics"})
+	v.SetDefault("trash.retention_days", 30)
+	v.SetDefault("trash.purge_spec", "0 0 3 * * *")
+	v.SetDefault("system.read_timeout", 15)
+	v.SetDefault("system.read_header_timeout", 5)
+	v.SetDefault("system.write_timeout", 30)
+	v.SetDefault("system.idle_timeout", 60)
+	v.SetDefault("system.shutdown_timeout", 30)
+	v.SetDefault("system.trusted_proxies", []string{})
+	v.SetDefault("agent.addr", "localhost:50051")
+	v.SetDefault("agent.timeout", 10)
+	v.SetDefault("health.timeout", 2)
+	v.SetDefault("health.disk_path", "logs")
+	v.SetDefault("health.min_free_disk", 100)
+	v.SetDefault("metrics.enable", false)
+	v.SetDefault("metrics.path", "/metrics")
+	v.SetDefault("metrics.cron_addr", ":9101")
+	v.SetDefault("tracing.service_name", "insight")
+	v.SetDefault("tracing.exporter", "otlp")
+	v.SetDefault("tracing.endpoint", "localhost:4317")
+	v.SetDefault("tracing.insecure", true)
+	v.SetDefault("tracing.file_path", "logs/traces.json")
+	v.SetDefault("tracing.sample_ratio", 1.0)
+	v.SetDefault("rate_limit.enable", true)
+	v.SetDefault("rate_limit.backend", "memory")
+	for name, policy := range map[string]autoload.RateLimitPolicy{
+		"default": {Limit: 300, Period: 60, By: "ip"},
+		"user":    {Limit: 600, Period: 60, By: "user"},
+		"login":   {Limit: 10, Period: 60, By: "ip"},
+		"agent":   {Limit: 10, Period: 60, By: "user"},
+	} {
+		v.SetDefault("rate_limit.policies."+name+".limit", policy.Limit)
+		v.SetDefault("rate_limit.policies."+name+".period", policy.Period)
+		v.SetDefault("rate_limit.policies."+name+".by", policy.By)
+	}
+	v.SetDefault("cors.allow_methods", []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"})
+	v.SetDefault("cors.allow_headers", []string{"Origin", "Content-Type", "Authorization", "If-Match", "X-Request-ID", "X-API-Key"})
+	v.SetDefault("cors.expose_headers", []string{
+		"refresh-access-token", "refresh-exp", "X-Request-ID", "ETag",
+		"RateLimit-Policy", "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "Retry-After",
+		"Deprecation", "Sunset", "Link",
+	})
+	v.SetDefault("cors.max_age", 43200)
+	v.SetDefault("tls.cert_file", "storage/certs/cert.pem")
+	v.SetDefault("tls.key_file", "storage/certs/key.pem")
+	v.SetDefault("tls.reload_interval", 10)
+	v.SetDefault("tls.min_version", "1.2")
+	v.SetDefault("tls.client_auth", "none")
+	v.SetDefault("tls.hsts.max_age", 31536000)
+	v.SetDefault("openapi.enable", true)
+	v.SetDefault("debug.enable", true)
+}
+
 func GetConfig() *Config {
 	once.Do(func() {
 		appConfig = LoadConfig()
 	})
 	return appConfig
 }
+
+// SetConfig replaces the configuration returned by GetConfig, so that tests can run
+// without a config file. It must be called before anything reads the configuration.
+func SetConfig(config *Config) {
+	once.Do(func() {})
+	appConfig = config
+}
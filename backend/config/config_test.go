@@ -17,17 +17,23 @@ func TestLoadAppConfig(t *testing.T) {
 	t.Logf("Application config loaded successfully")
 }
 
-func TestLoadMySQLConfig(t *testing.T) {
+func TestLoadDatabaseConfig(t *testing.T) {
 	config := GetConfig()
 	assert.NotNil(t, config, "Config object should not be nil")
 
-	mysqlConfig := config.MySQL
+	dbConfig := config.Database
 
-	assert.NotEmpty(t, mysqlConfig.Host, "MySQL host should not be empty")
-	assert.NotZero(t, mysqlConfig.Port, "MySQL port should not be zero")
-	assert.NotEmpty(t, mysqlConfig.Username, "MySQL username should not be empty")
-	assert.NotEmpty(t, mysqlConfig.Password, "MySQL password should not be empty")
-	assert.NotEmpty(t, mysqlConfig.Database, "MySQL database name should not be empty")
+	assert.Contains(t, []string{"mysql", "postgres", "sqlite"}, dbConfig.Driver, "Database driver should be supported")
+	if dbConfig.Driver == "sqlite" {
+		t.Logf("SQLite config loaded successfully")
+		return
+	}
 
-	t.Logf("MySQL config loaded successfully")
+	assert.NotEmpty(t, dbConfig.Host, "Database host should not be empty")
+	assert.NotZero(t, dbConfig.Port, "Database port should not be zero")
+	assert.NotEmpty(t, dbConfig.Username, "Database username should not be empty")
+	assert.NotEmpty(t, dbConfig.Password, "Database password should not be empty")
+	assert.NotEmpty(t, dbConfig.Database, "Database name should not be empty")
+
+	t.Logf("Database config loaded successfully")
 }
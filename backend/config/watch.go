@@ -0,0 +1,40 @@
+package config
+
+import (
+	"log"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
+)
+
+var (
+	watchOnce   sync.Once
+	listenersMu sync.Mutex
+	listeners   []func(*Config)
+)
+
+// OnChange registers fn to be called with the re-parsed config whenever the config file changes.
+// The config returned by GetConfig is not replaced, so only subscribers see the new values.
+func OnChange(fn func(*Config)) {
+	listenersMu.Lock()
+	listeners = append(listeners, fn)
+	listenersMu.Unlock()
+
+	watchOnce.Do(func() {
+		GetConfig()
+		viper.OnConfigChange(func(fsnotify.Event) {
+			var changed Config
+			if err := viper.Unmarshal(&changed); err != nil {
+				log.Printf("Error reloading config, %s", err)
+				return
+			}
+			listenersMu.Lock()
+			defer listenersMu.Unlock()
+			for _, listener := range listeners {
+				listener(&changed)
+			}
+		})
+		viper.WatchConfig()
+	})
+}
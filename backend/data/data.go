@@ -9,8 +9,8 @@ var once sync.Once
 
 func InitData() {
 	once.Do(func() {
-		if c.GetConfig().MySQL.Enable {
-			initMysql()
+		if c.GetConfig().Database.Enable {
+			initDatabase()
 		}
 	})
 }
@@ -0,0 +1,193 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	c "insight/config"
+	"insight/config/autoload"
+	log "insight/internal/pkg/logger"
+	"insight/internal/pkg/metrics"
+	"insight/internal/pkg/tracing"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"gorm.io/gorm/schema"
+)
+
+var (
+	db       *gorm.DB
+	replicas *resolver
+)
+
+// dialectors 按驱动名称构造对应的 gorm.Dialector
+var dialectors = map[string]func(cfg autoload.DatabaseConfig) gorm.Dialector{
+	"mysql":    mysqlDialector,
+	"postgres": postgresDialector,
+	"sqlite":   sqliteDialector,
+}
+
+// DB returns the shared database handle. It is nil until InitData has run
+// with database.enable set.
+func DB() *gorm.DB {
+	return db
+}
+
+type Writer interface {
+	Printf(string, ...interface{})
+}
+
+type WriterLog struct{}
+
+func (w WriterLog) Printf(format string, args ...interface{}) {
+	if c.GetConfig().Database.PrintSql {
+		log.Logger.Sugar().Infof(format, args...)
+	}
+}
+
+func GetLoggerLevel(level string) logger.LogLevel {
+	switch level {
+	case "silent":
+		return logger.Silent
+	case "error":
+		return logger.Error
+	case "warn":
+		return logger.Warn
+	case "info":
+		return logger.Info
+	default:
+		return logger.Info
+	}
+}
+
+// Open opens a database connection for the given config and applies the pool settings.
+func Open(cfg autoload.DatabaseConfig) (*gorm.DB, error) {
+	newDialector, ok := dialectors[cfg.Driver]
+	if !ok {
+		return nil, fmt.Errorf("unsupported database driver %q", cfg.Driver)
+	}
+
+	logConfig := logger.New(
+		WriterLog{},
+		logger.Config{
+			SlowThreshold:             0,
+			LogLevel:                  GetLoggerLevel(cfg.LogLevel), // Log level
+			IgnoreRecordNotFoundError: false,                        // Ignore ErrRecordNotFound error for logger
+			Colorful:                  true,                         // Disable color
+		},
+	)
+
+	configs := &gorm.Config{
+		NamingStrategy: schema.NamingStrategy{
+			TablePrefix: cfg.TablePrefix, // 表名前缀
+		},
+		Logger: logConfig,
+		// 将唯一键冲突等驱动错误转换为 gorm.ErrDuplicatedKey 等通用错误
+		TranslateError: true,
+	}
+
+	conn, err := gorm.Open(newDialector(cfg), configs)
+	if err != nil {
+		return nil, err
+	}
+	if err := conn.Use(metrics.GormPlugin{}); err != nil {
+		return nil, err
+	}
+	if err := conn.Use(tracing.GormPlugin{}); err != nil {
+		return nil, err
+	}
+
+	sqlDB, err := conn.DB()
+	if err != nil {
+		return nil, err
+	}
+	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	sqlDB.SetConnMaxLifetime(time.Duration(cfg.MaxLifetime) * time.Second)
+	if cfg.Driver == "sqlite" && isSqliteMemory(cfg.Path) {
+		// 内存库随最后一个连接关闭而销毁，固定保留一个常驻连接
+		sqlDB.SetMaxOpenConns(1)
+		sqlDB.SetMaxIdleConns(1)
+		sqlDB.SetConnMaxLifetime(0)
+	}
+	return conn, nil
+}
+
+func initDatabase() {
+	cfg := c.GetConfig().Database
+
+	var err error
+	db, err = Open(cfg)
+	if err != nil {
+		panic("failed to connect database, err: " + err.Error())
+	}
+	log.Logger.Info("Database connected successfully", zap.String("driver", cfg.Driver))
+	if sqlDB, err := db.DB(); err == nil {
+		registerDBStats(sqlDB, "primary")
+	}
+
+	if len(cfg.Replicas) > 0 {
+		replicas, err = newResolver(cfg)
+		if err == nil {
+			err = db.Use(replicas)
+		}
+		if err != nil {
+			panic("failed to connect database replicas, err: " + err.Error())
+		}
+		log.Logger.Info("Database replicas connected successfully", zap.Int("replicas", len(cfg.Replicas)))
+		for _, rep := range replicas.replicas {
+			registerDBStats(rep.db, rep.name)
+		}
+	}
+}
+
+func registerDBStats(sqlDB *sql.DB, name string) {
+	if err := metrics.RegisterDBStats(sqlDB, name); err != nil {
+		log.Logger.Warn("Failed to register database pool metrics", zap.String("db", name), zap.Error(err))
+	}
+}
+
+// Ping 检查主库连接是否可用
+func Ping(ctx context.Context) error {
+	if db == nil {
+		return errors.New("database not initialized")
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+// Stats 返回 db 主库与从库的连接池状态，主库的键为 primary，从库的键为从库地址
+func Stats(db *gorm.DB) (map[string]sql.DBStats, error) {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	stats := map[string]sql.DBStats{"primary": sqlDB.Stats()}
+	if r, ok := db.Config.Plugins[resolverName].(*resolver); ok {
+		for _, rep := range r.replicas {
+			stats[rep.name] = rep.db.Stats()
+		}
+	}
+	return stats, nil
+}
+
+// Close 关闭主库与从库连接
+func Close() error {
+	if replicas != nil {
+		replicas.Close()
+	}
+	if db == nil {
+		return nil
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
+}
@@ -0,0 +1,47 @@
+package dbtest
+
+import (
+	"fmt"
+	"insight/config/autoload"
+	"insight/data"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+var databases atomic.Int64
+
+// Open 打开独立的 sqlite 内存数据库，不同测试之间互相隔离，测试结束时关闭
+func Open(t testing.TB) *gorm.DB {
+	t.Helper()
+	return OpenPath(t, fmt.Sprintf("file:dbtest_%d?mode=memory", databases.Add(1)))
+}
+
+// OpenFile 在临时目录中打开 sqlite 文件数据库，需要多个连接并发访问时使用
+func OpenFile(t testing.TB) *gorm.DB {
+	t.Helper()
+	return OpenPath(t, filepath.Join(t.TempDir(), "test.db"))
+}
+
+// OpenPath 按路径打开 sqlite 数据库，同一路径打开多次时共享数据
+func OpenPath(t testing.TB, path string) *gorm.DB {
+	t.Helper()
+	return OpenConfig(t, autoload.DatabaseConfig{Driver: "sqlite", Path: path, LogLevel: "silent"})
+}
+
+// OpenConfig 按配置打开数据库，测试结束时关闭连接
+func OpenConfig(t testing.TB, cfg autoload.DatabaseConfig) *gorm.DB {
+	t.Helper()
+	db, err := data.Open(cfg)
+	if err != nil {
+		t.Fatalf("dbtest: open database: %v", err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			_ = sqlDB.Close()
+		}
+	})
+	return db
+}
@@ -0,0 +1,10 @@
+package data
+
+import "gorm.io/gorm"
+
+// SetDB 替换全局连接并返回恢复函数，供 data_test 包中的测试使用
+func SetDB(conn *gorm.DB) (restore func()) {
+	origin := db
+	db = conn
+	return func() { db = origin }
+}
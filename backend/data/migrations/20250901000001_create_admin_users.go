@@ -0,0 +1,47 @@
+package migrations
+
+import (
+	"insight/data/migrator"
+	"time"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+type adminUser20250901000001 struct {
+	ID        uint `gorm:"primarykey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt uint           `gorm:"not null;default:0;index;uniqueIndex:uk_admin_user_username,priority:2"`
+	IsAdmin   int8           `gorm:"not null;default:0"`
+	NickName  string         `gorm:"size:64"`
+	Username  string         `gorm:"size:64;not null;uniqueIndex:uk_admin_user_username,priority:1"`
+	Password  string         `gorm:"size:255;not null"`
+	Email     string         `gorm:"size:128"`
+	Mobile    string         `gorm:"size:32"`
+	Roles     datatypes.JSON ``
+	Avatar    string         `gorm:"size:255"`
+	Status    int8           `gorm:"not null;default:1"`
+}
+
+func (adminUser20250901000001) TableName() string {
+	return "a_admin_user"
+}
+
+func init() {
+	migrator.Register(&migrator.Migration{
+		Version: "20250901000001",
+		Name:    "create_admin_users",
+		Up: func(tx *gorm.DB) error {
+			if !migrator.HasTable(tx, &adminUser20250901000001{}) {
+				return tx.Migrator().CreateTable(&adminUser20250901000001{})
+			}
+			// 此前通过 AutoMigrate 创建的表缺少唯一索引
+			return migrator.CreateMissingIndexes(tx, &adminUser20250901000001{},
+				"idx_a_admin_user_deleted_at", "uk_admin_user_username")
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&adminUser20250901000001{})
+		},
+	})
+}
@@ -0,0 +1,45 @@
+package migrations
+
+import (
+	"insight/data/migrator"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type permission20250901000002 struct {
+	ID        uint `gorm:"primarykey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt uint   `gorm:"not null;default:0;index;uniqueIndex:uk_permissions_method_route,priority:3"`
+	Name      string `gorm:"size:60;not null"`
+	Desc      string `gorm:"size:255"`
+	Method    string `gorm:"size:16;not null;uniqueIndex:uk_permissions_method_route,priority:1"`
+	Route     string `gorm:"size:255;not null;uniqueIndex:uk_permissions_method_route,priority:2"`
+	Func      string `gorm:"size:128"`
+	FuncPath  string `gorm:"size:255"`
+	IsAuth    int8   `gorm:"not null;default:0"`
+	Sort      int32  `gorm:"not null;default:0"`
+}
+
+func (permission20250901000002) TableName() string {
+	return "permissions"
+}
+
+func init() {
+	migrator.Register(&migrator.Migration{
+		Version: "20250901000002",
+		Name:    "create_permissions",
+		Up: func(tx *gorm.DB) error {
+			if !migrator.HasTable(tx, &permission20250901000002{}) {
+				return tx.Migrator().CreateTable(&permission20250901000002{})
+			}
+			// 此前通过 AutoMigrate 创建的表缺少唯一索引
+			return migrator.CreateMissingIndexes(tx, &permission20250901000002{},
+				"idx_permissions_deleted_at", "uk_permissions_method_route")
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&permission20250901000002{})
+		},
+	})
+}
@@ -0,0 +1,46 @@
+package migrations
+
+import (
+	"insight/data/migrator"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type role20250901000003 struct {
+	ID        uint `gorm:"primarykey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt uint   `gorm:"not null;default:0;index;uniqueIndex:uk_roles_code,priority:2"`
+	Code      string `gorm:"size:64;not null;uniqueIndex:uk_roles_code,priority:1"`
+	Name      string `gorm:"size:64;not null"`
+	Desc      string `gorm:"size:255"`
+	Status    int8   `gorm:"not null;default:1"`
+	Sort      int32  `gorm:"not null;default:0"`
+}
+
+func (role20250901000003) TableName() string {
+	return "roles"
+}
+
+type rolePermission20250901000003 struct {
+	RoleID       uint `gorm:"primaryKey;autoIncrement:false"`
+	PermissionID uint `gorm:"primaryKey;autoIncrement:false;index"`
+}
+
+func (rolePermission20250901000003) TableName() string {
+	return "role_permissions"
+}
+
+func init() {
+	migrator.Register(&migrator.Migration{
+		Version: "20250901000003",
+		Name:    "create_roles",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&role20250901000003{}, &rolePermission20250901000003{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&rolePermission20250901000003{}, &role20250901000003{})
+		},
+	})
+}
@@ -0,0 +1,40 @@
+package migrations
+
+import (
+	"insight/data/migrator"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type menu20250901000004 struct {
+	ID        uint `gorm:"primarykey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt uint   `gorm:"not null;default:0;index;uniqueIndex:uk_menus_name,priority:2"`
+	ParentID  uint   `gorm:"not null;default:0;index"`
+	Name      string `gorm:"size:64;not null;uniqueIndex:uk_menus_name,priority:1"`
+	Title     string `gorm:"size:64;not null"`
+	Path      string `gorm:"size:255"`
+	Component string `gorm:"size:255"`
+	Icon      string `gorm:"size:64"`
+	Status    int8   `gorm:"not null;default:1"`
+	Sort      int32  `gorm:"not null;default:0"`
+}
+
+func (menu20250901000004) TableName() string {
+	return "menus"
+}
+
+func init() {
+	migrator.Register(&migrator.Migration{
+		Version: "20250901000004",
+		Name:    "create_menus",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&menu20250901000004{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&menu20250901000004{})
+		},
+	})
+}
@@ -0,0 +1,38 @@
+package migrations
+
+import (
+	"insight/data/migrator"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type dictionary20250901000005 struct {
+	ID        uint `gorm:"primarykey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt uint   `gorm:"not null;default:0;index;uniqueIndex:uk_dictionaries_type_value,priority:3"`
+	Type      string `gorm:"size:64;not null;uniqueIndex:uk_dictionaries_type_value,priority:1"`
+	Label     string `gorm:"size:64;not null"`
+	Value     string `gorm:"size:128;not null;uniqueIndex:uk_dictionaries_type_value,priority:2"`
+	Remark    string `gorm:"size:255"`
+	Status    int8   `gorm:"not null;default:1"`
+	Sort      int32  `gorm:"not null;default:0"`
+}
+
+func (dictionary20250901000005) TableName() string {
+	return "dictionaries"
+}
+
+func init() {
+	migrator.Register(&migrator.Migration{
+		Version: "20250901000005",
+		Name:    "create_dictionaries",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&dictionary20250901000005{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&dictionary20250901000005{})
+		},
+	})
+}
@@ -0,0 +1,31 @@
+package migrations
+
+import (
+	"insight/data/migrator"
+
+	"gorm.io/gorm"
+)
+
+// 早期版本的 create_permissions 创建的是 route 唯一索引，而同一路由可以对应多个请求方法，
+// 已执行过该版本的数据库在此替换为 method + route 唯一索引；其他数据库的索引已由 create_permissions 创建。
+func init() {
+	migrator.Register(&migrator.Migration{
+		Version: "20250901000006",
+		Name:    "permissions_unique_method_route",
+		Up: func(tx *gorm.DB) error {
+			if migrator.HasIndex(tx, "permissions", "uk_permissions_route") {
+				if err := tx.Migrator().DropIndex("permissions", "uk_permissions_route"); err != nil {
+					return err
+				}
+			}
+			if migrator.HasIndex(tx, "permissions", "uk_permissions_method_route") {
+				return nil
+			}
+			return tx.Exec("CREATE UNIQUE INDEX uk_permissions_method_route ON permissions (method, route, deleted_at)").Error
+		},
+		Down: func(tx *gorm.DB) error {
+			// method + route 唯一索引属于 create_permissions，回滚时保留，不恢复错误的 route 唯一索引
+			return nil
+		},
+	})
+}
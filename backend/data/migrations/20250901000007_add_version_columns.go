@@ -0,0 +1,36 @@
+package migrations
+
+import (
+	"insight/data/migrator"
+
+	"gorm.io/gorm"
+)
+
+// 乐观锁版本号，已有数据从 1 开始
+var versionedTables20250901000007 = []string{"a_admin_user", "permissions", "roles", "menus", "dictionaries"}
+
+func init() {
+	migrator.Register(&migrator.Migration{
+		Version: "20250901000007",
+		Name:    "add_version_columns",
+		Up: func(tx *gorm.DB) error {
+			for _, table := range versionedTables20250901000007 {
+				if migrator.HasColumn(tx, table, "version") {
+					continue
+				}
+				if err := tx.Exec("ALTER TABLE " + table + " ADD COLUMN version BIGINT NOT NULL DEFAULT 1").Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		Down: func(tx *gorm.DB) error {
+			for _, table := range versionedTables20250901000007 {
+				if err := tx.Exec("ALTER TABLE " + table + " DROP COLUMN version").Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	})
+}
@@ -0,0 +1,34 @@
+package migrations
+
+import (
+	"insight/data/migrator"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type deprecatedRouteHit20261019120000 struct {
+	ID        uint   `gorm:"primarykey"`
+	Day       string `gorm:"size:10;not null;uniqueIndex:uk_deprecated_route_hits,priority:1"`
+	Method    string `gorm:"size:16;not null;uniqueIndex:uk_deprecated_route_hits,priority:2"`
+	Route     string `gorm:"size:255;not null;uniqueIndex:uk_deprecated_route_hits,priority:3"`
+	Hits      int64  `gorm:"not null;default:0"`
+	UpdatedAt time.Time
+}
+
+func (deprecatedRouteHit20261019120000) TableName() string {
+	return "deprecated_route_hits"
+}
+
+func init() {
+	migrator.Register(&migrator.Migration{
+		Version: "20261019120000",
+		Name:    "create_deprecated_route_hits",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&deprecatedRouteHit20261019120000{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&deprecatedRouteHit20261019120000{})
+		},
+	})
+}
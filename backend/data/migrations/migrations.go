@@ -0,0 +1,5 @@
+// Package migrations 存放版本化的数据库迁移文件。
+//
+// 每个迁移文件在 init 中通过 migrator.Register 注册，并使用文件内定义的表结构快照，
+// 避免后续模型变化影响历史迁移。新迁移请使用 `insight migrate create <name>` 生成。
+package migrations
@@ -0,0 +1,115 @@
+package migrations
+
+import (
+	"insight/data/dbtest"
+	"insight/data/migrator"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// legacyAdminUser 与 legacyPermission 为引入版本化迁移前 AutoMigrate 创建的表结构，没有唯一索引
+type legacyAdminUser struct {
+	ID        uint `gorm:"primarykey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt uint `gorm:"not null;default:0;index"`
+	IsAdmin   int8
+	NickName  string
+	Username  string
+	Password  string
+	Email     string
+	Mobile    string
+	Roles     datatypes.JSON
+	Avatar    string
+	Status    int8
+}
+
+func (legacyAdminUser) TableName() string {
+	return "a_admin_user"
+}
+
+type legacyPermission struct {
+	ID        uint `gorm:"primarykey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt uint `gorm:"not null;default:0;index"`
+	Name      string
+	Desc      string
+	Method    string
+	Route     string
+	Func      string
+	FuncPath  string
+	IsAuth    int8
+	Sort      int32
+}
+
+func (legacyPermission) TableName() string {
+	return "permissions"
+}
+
+func assertSchema(t *testing.T, db *gorm.DB) {
+	t.Helper()
+	assert.True(t, db.Migrator().HasIndex("a_admin_user", "uk_admin_user_username"))
+	assert.True(t, db.Migrator().HasIndex("permissions", "uk_permissions_method_route"))
+	assert.False(t, db.Migrator().HasIndex("permissions", "uk_permissions_route"))
+	assert.True(t, db.Migrator().HasColumn("permissions", "version"))
+
+	// 同一路由的不同请求方法
+	require.NoError(t, db.Exec("INSERT INTO permissions (name, method, route) VALUES ('a', 'GET', '/x'), ('b', 'PUT', '/x')").Error)
+	assert.Error(t, db.Exec("INSERT INTO permissions (name, method, route) VALUES ('c', 'GET', '/x')").Error)
+}
+
+func TestUpFresh(t *testing.T) {
+	db := dbtest.OpenFile(t)
+	_, err := migrator.New(db).Up(0)
+	require.NoError(t, err)
+	assertSchema(t, db)
+}
+
+func TestUpFromAutoMigrate(t *testing.T) {
+	db := dbtest.OpenFile(t)
+	require.NoError(t, db.AutoMigrate(&legacyAdminUser{}, &legacyPermission{}))
+	require.NoError(t, db.Create(&legacyPermission{Name: "legacy", Method: "GET", Route: "/legacy"}).Error)
+
+	_, err := migrator.New(db).Up(0)
+	require.NoError(t, err)
+	assertSchema(t, db)
+	var count int64
+	db.Table("permissions").Where("route = ?", "/legacy").Count(&count)
+	assert.EqualValues(t, 1, count)
+}
+
+func TestUpFromRouteIndex(t *testing.T) {
+	// 早期版本的 create_permissions 创建的是 route 唯一索引
+	db := dbtest.OpenFile(t)
+	require.NoError(t, db.AutoMigrate(&legacyPermission{}))
+	require.NoError(t, db.Exec("CREATE UNIQUE INDEX uk_permissions_route ON permissions (route, deleted_at)").Error)
+
+	_, err := migrator.New(db).Up(0)
+	require.NoError(t, err)
+	assertSchema(t, db)
+}
+
+func TestDownKeepsMethodRouteIndex(t *testing.T) {
+	db := dbtest.OpenFile(t)
+	m := migrator.New(db)
+	_, err := m.Up(0)
+	require.NoError(t, err)
+
+	// 回滚到 permissions_unique_method_route 之前
+	steps := 0
+	for _, migration := range migrator.Registered() {
+		if migration.Version >= "20250901000006" {
+			steps++
+		}
+	}
+	_, err = m.Down(steps)
+	require.NoError(t, err)
+	assert.True(t, db.Migrator().HasIndex("permissions", "uk_permissions_method_route"))
+	assert.False(t, db.Migrator().HasIndex("permissions", "uk_permissions_route"))
+}
@@ -0,0 +1,59 @@
+package migrator
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+)
+
+var nameSanitizer = regexp.MustCompile(`[^a-z0-9]+`)
+
+var migrationTemplate = template.Must(template.New("migration").Parse(`package migrations
+
+import (
+	"insight/data/migrator"
+
+	"gorm.io/gorm"
+)
+
+func init() {
+	migrator.Register(&migrator.Migration{
+		Version: "{{.Version}}",
+		Name:    "{{.Name}}",
+		Up: func(tx *gorm.DB) error {
+			return nil
+		},
+		Down: func(tx *gorm.DB) error {
+			return nil
+		},
+	})
+}
+`))
+
+// Create 在 dir 目录下生成新的迁移文件，返回文件路径
+func Create(dir, name string) (string, error) {
+	name = strings.Trim(nameSanitizer.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if name == "" {
+		return "", errors.New("migration name is empty")
+	}
+
+	version := time.Now().Format("20060102150405")
+	path := filepath.Join(dir, fmt.Sprintf("%s_%s.go", version, name))
+	if _, err := os.Stat(path); err == nil {
+		return "", fmt.Errorf("migration file %s already exists", path)
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_EXCL, 0o644)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	err = migrationTemplate.Execute(file, struct{ Version, Name string }{version, name})
+	return path, err
+}
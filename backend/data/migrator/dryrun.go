@@ -0,0 +1,28 @@
+package migrator
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm/logger"
+)
+
+// recorder 收集 dry-run 模式下生成的 SQL
+type recorder struct {
+	migrator *Migrator
+}
+
+func (r *recorder) LogMode(logger.LogLevel) logger.Interface {
+	return r
+}
+
+func (r *recorder) Info(context.Context, string, ...interface{}) {}
+
+func (r *recorder) Warn(context.Context, string, ...interface{}) {}
+
+func (r *recorder) Error(context.Context, string, ...interface{}) {}
+
+func (r *recorder) Trace(_ context.Context, _ time.Time, fc func() (sql string, rowsAffected int64), _ error) {
+	sql, _ := fc()
+	r.migrator.Statements = append(r.migrator.Statements, sql)
+}
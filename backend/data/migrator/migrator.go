@@ -0,0 +1,405 @@
+package migrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"insight/data"
+	"os"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrLocked 迁移锁被其他进程持有且在等待时间内未释放
+var ErrLocked = errors.New("migration lock is held by another process")
+
+// ErrLockLost 迁移过程中锁因长时间未续期被其他进程清理，期间可能有其他进程同时执行迁移
+var ErrLockLost = errors.New("migration lock was lost while migrating")
+
+// Migration 一次版本化的数据库变更
+type Migration struct {
+	Version string                  // 版本号，使用 yyyyMMddHHmmss 时间戳保证执行顺序
+	Name    string                  // 迁移名称
+	Up      func(tx *gorm.DB) error // 执行变更
+	Down    func(tx *gorm.DB) error // 回滚变更
+}
+
+// ID 返回迁移文件的完整标识
+func (m *Migration) ID() string {
+	return m.Version + "_" + m.Name
+}
+
+var registry = map[string]*Migration{}
+
+// Register 注册迁移，通常在迁移文件的 init 中调用
+func Register(m *Migration) {
+	if _, ok := registry[m.Version]; ok {
+		panic("duplicate migration version " + m.Version)
+	}
+	registry[m.Version] = m
+}
+
+// Registered 返回按版本号升序排列的全部迁移
+func Registered() []*Migration {
+	migrations := make([]*Migration, 0, len(registry))
+	for _, m := range registry {
+		migrations = append(migrations, m)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations
+}
+
+// SchemaMigration 迁移历史表
+type SchemaMigration struct {
+	Version   string    `gorm:"primaryKey;size:32"`
+	Name      string    `gorm:"size:255;not null"`
+	Batch     int       `gorm:"not null"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// schemaMigrationLock 迁移锁表，同一时间只允许存在 id = 1 的一行
+type schemaMigrationLock struct {
+	ID       uint   `gorm:"primaryKey;autoIncrement:false"`
+	Owner    string `gorm:"size:128;not null"`
+	LockedAt int64  `gorm:"not null"`
+}
+
+func (schemaMigrationLock) TableName() string {
+	return "schema_migrations_lock"
+}
+
+// Status 迁移执行状态，Applied 为 nil 表示尚未执行
+type Status struct {
+	Migration *Migration
+	Applied   *SchemaMigration
+}
+
+type Migrator struct {
+	db         *gorm.DB
+	migrations []*Migration
+
+	// DryRun 只生成 SQL 而不执行，生成的语句写入 Statements
+	DryRun     bool
+	Statements []string
+	// LockWait 等待其他进程释放迁移锁的最长时间
+	LockWait time.Duration
+	// LockTTL 超过该时间未续期的锁视为持有者已异常退出，持有者每 LockTTL/3 续期一次
+	LockTTL time.Duration
+}
+
+// New 使用全部已注册的迁移创建 Migrator，迁移的读写都在主库上进行
+func New(db *gorm.DB) *Migrator {
+	return &Migrator{
+		db:         db.WithContext(data.UsePrimary(context.Background())),
+		migrations: Registered(),
+		LockWait:   time.Minute,
+		LockTTL:    10 * time.Minute,
+	}
+}
+
+// Applied 返回已执行的迁移记录，按版本号升序
+func (m *Migrator) Applied() ([]SchemaMigration, error) {
+	var records []SchemaMigration
+	if !m.db.Migrator().HasTable(&SchemaMigration{}) {
+		return records, nil
+	}
+	err := m.db.Order("version").Find(&records).Error
+	return records, err
+}
+
+// Status 返回每个已注册迁移的执行状态
+func (m *Migrator) Status() ([]Status, error) {
+	applied, err := m.appliedMap()
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := Status{Migration: migration}
+		if record, ok := applied[migration.Version]; ok {
+			status.Applied = &record
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// Pending 返回尚未执行的迁移
+func (m *Migrator) Pending() ([]*Migration, error) {
+	applied, err := m.appliedMap()
+	if err != nil {
+		return nil, err
+	}
+	var pending []*Migration
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; !ok {
+			pending = append(pending, migration)
+		}
+	}
+	return pending, nil
+}
+
+// Up 按顺序执行未执行的迁移，steps <= 0 表示全部执行
+func (m *Migrator) Up(steps int) (done []*Migration, err error) {
+	err = m.withLock(func() error {
+		pending, err := m.Pending()
+		if err != nil {
+			return err
+		}
+		if steps > 0 && steps < len(pending) {
+			pending = pending[:steps]
+		}
+		batch, err := m.nextBatch()
+		if err != nil {
+			return err
+		}
+		for _, migration := range pending {
+			if err := m.apply(migration, batch); err != nil {
+				return fmt.Errorf("migrate up %s: %w", migration.ID(), err)
+			}
+			done = append(done, migration)
+		}
+		return nil
+	})
+	return
+}
+
+// Down 按倒序回滚最近执行的 steps 个迁移，steps <= 0 时回滚一个
+func (m *Migrator) Down(steps int) (done []*Migration, err error) {
+	err = m.withLock(func() error {
+		latest, err := m.latest(steps)
+		if err != nil {
+			return err
+		}
+		for _, migration := range latest {
+			if err := m.revert(migration); err != nil {
+				return fmt.Errorf("migrate down %s: %w", migration.ID(), err)
+			}
+			done = append(done, migration)
+		}
+		return nil
+	})
+	return
+}
+
+// Redo 回滚最近执行的 steps 个迁移后重新执行
+func (m *Migrator) Redo(steps int) (done []*Migration, err error) {
+	err = m.withLock(func() error {
+		latest, err := m.latest(steps)
+		if err != nil {
+			return err
+		}
+		for _, migration := range latest {
+			if err := m.revert(migration); err != nil {
+				return fmt.Errorf("migrate down %s: %w", migration.ID(), err)
+			}
+		}
+		batch, err := m.nextBatch()
+		if err != nil {
+			return err
+		}
+		for i := len(latest) - 1; i >= 0; i-- {
+			if err := m.apply(latest[i], batch); err != nil {
+				return fmt.Errorf("migrate up %s: %w", latest[i].ID(), err)
+			}
+			done = append(done, latest[i])
+		}
+		return nil
+	})
+	return
+}
+
+// latest 返回最近执行的 steps 个迁移，按执行倒序排列
+func (m *Migrator) latest(steps int) ([]*Migration, error) {
+	if steps <= 0 {
+		steps = 1
+	}
+	applied, err := m.Applied()
+	if err != nil {
+		return nil, err
+	}
+	migrations := make(map[string]*Migration, len(m.migrations))
+	for _, migration := range m.migrations {
+		migrations[migration.Version] = migration
+	}
+	var latest []*Migration
+	for i := len(applied) - 1; i >= 0 && len(latest) < steps; i-- {
+		migration, ok := migrations[applied[i].Version]
+		if !ok {
+			return nil, fmt.Errorf("migration %s_%s is applied but not registered", applied[i].Version, applied[i].Name)
+		}
+		latest = append(latest, migration)
+	}
+	return latest, nil
+}
+
+func (m *Migrator) apply(migration *Migration, batch int) error {
+	record := &SchemaMigration{
+		Version:   migration.Version,
+		Name:      migration.Name,
+		Batch:     batch,
+		AppliedAt: time.Now(),
+	}
+	return m.run(func(tx *gorm.DB) error {
+		if err := migration.Up(tx); err != nil {
+			return err
+		}
+		return tx.Create(record).Error
+	})
+}
+
+func (m *Migrator) revert(migration *Migration) error {
+	if migration.Down == nil {
+		return errors.New("migration has no down step")
+	}
+	return m.run(func(tx *gorm.DB) error {
+		if err := migration.Down(tx); err != nil {
+			return err
+		}
+		return tx.Where("version = ?", migration.Version).Delete(&SchemaMigration{}).Error
+	})
+}
+
+// run 在事务中执行迁移；dry-run 模式下只记录生成的 SQL
+func (m *Migrator) run(fn func(tx *gorm.DB) error) (err error) {
+	if !m.DryRun {
+		return m.db.Transaction(fn)
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("migration does not support dry-run: %v", r)
+		}
+	}()
+	return fn(m.db.Session(&gorm.Session{DryRun: true, Logger: &recorder{migrator: m}}))
+}
+
+func (m *Migrator) nextBatch() (int, error) {
+	if m.DryRun && !m.db.Migrator().HasTable(&SchemaMigration{}) {
+		return 1, nil
+	}
+	var batch int
+	err := m.db.Model(&SchemaMigration{}).Select("COALESCE(MAX(batch), 0)").Scan(&batch).Error
+	return batch + 1, err
+}
+
+func (m *Migrator) appliedMap() (map[string]SchemaMigration, error) {
+	records, err := m.Applied()
+	if err != nil {
+		return nil, err
+	}
+	applied := make(map[string]SchemaMigration, len(records))
+	for _, record := range records {
+		applied[record.Version] = record
+	}
+	return applied, nil
+}
+
+// withLock 获取迁移锁后执行 fn，避免多个实例同时启动时重复迁移
+func (m *Migrator) withLock(fn func() error) error {
+	if m.DryRun {
+		return fn()
+	}
+	if err := m.db.AutoMigrate(&SchemaMigration{}, &schemaMigrationLock{}); err != nil {
+		return err
+	}
+
+	hostname, _ := os.Hostname()
+	owner := fmt.Sprintf("%s:%d", hostname, os.Getpid())
+	deadline := time.Now().Add(m.LockWait)
+	for {
+		// 清理持有者异常退出后遗留的锁
+		m.db.Where("id = ? AND locked_at < ?", 1, time.Now().Add(-m.LockTTL).Unix()).Delete(&schemaMigrationLock{})
+
+		lock := &schemaMigrationLock{ID: 1, Owner: owner, LockedAt: time.Now().Unix()}
+		if err := m.db.Create(lock).Error; err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			var holder schemaMigrationLock
+			m.db.Where("id = ?", 1).Limit(1).Find(&holder)
+			return fmt.Errorf("%w: %s", ErrLocked, holder.Owner)
+		}
+		time.Sleep(time.Second)
+	}
+	defer m.db.Where("id = ? AND owner = ?", 1, owner).Delete(&schemaMigrationLock{})
+
+	// 迁移期间定期续期，避免耗时较长的迁移被当作异常退出而丢失锁
+	stop := make(chan struct{})
+	lost := make(chan bool, 1)
+	go func() { lost <- m.heartbeat(owner, stop) }()
+	err := fn()
+	close(stop)
+	if <-lost {
+		return errors.Join(err, ErrLockLost)
+	}
+	return err
+}
+
+// heartbeat 每 LockTTL/3 更新一次 locked_at，直到 stop 关闭；返回锁是否已不属于 owner
+func (m *Migrator) heartbeat(owner string, stop <-chan struct{}) bool {
+	if m.LockTTL <= 0 {
+		<-stop
+		return false
+	}
+	ticker := time.NewTicker(m.LockTTL / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return false
+		case <-ticker.C:
+			result := m.db.Model(&schemaMigrationLock{}).Where("id = ? AND owner = ?", 1, owner).
+				Update("locked_at", time.Now().Unix())
+			// 更新失败时下次重试，只有锁已被其他进程清理时才视为丢失
+			if result.Error == nil && result.RowsAffected == 0 {
+				<-stop
+				return true
+			}
+		}
+	}
+}
+
+// HasTable 判断表是否存在，dry-run 模式下无法查询数据库，始终返回 false
+func HasTable(tx *gorm.DB, table any) bool {
+	if tx.DryRun {
+		return false
+	}
+	return tx.Migrator().HasTable(table)
+}
+
+// HasIndex 判断表中是否存在索引，dry-run 模式下始终返回 false
+func HasIndex(tx *gorm.DB, table any, name string) bool {
+	if tx.DryRun {
+		return false
+	}
+	return tx.Migrator().HasIndex(table, name)
+}
+
+// CreateMissingIndexes 创建 model 标签中声明而表中不存在的索引，用于补齐此前通过 AutoMigrate 创建的表
+func CreateMissingIndexes(tx *gorm.DB, model any, names ...string) error {
+	for _, name := range names {
+		if HasIndex(tx, model, name) {
+			continue
+		}
+		if err := tx.Migrator().CreateIndex(model, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// HasColumn 判断表中是否存在列，dry-run 模式下始终返回 false
+func HasColumn(tx *gorm.DB, table, column string) bool {
+	if tx.DryRun {
+		return false
+	}
+	return tx.Migrator().HasColumn(table, column)
+}
@@ -0,0 +1,158 @@
+package migrator
+
+import (
+	"insight/data/dbtest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type widget struct {
+	ID   uint   `gorm:"primarykey"`
+	Name string `gorm:"size:64"`
+}
+
+func newTestMigrator(t *testing.T) *Migrator {
+	m := New(dbtest.Open(t))
+	m.LockWait = 0
+	m.migrations = []*Migration{
+		{
+			Version: "20250101000001",
+			Name:    "create_widgets",
+			Up: func(tx *gorm.DB) error {
+				return tx.Migrator().CreateTable(&widget{})
+			},
+			Down: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable(&widget{})
+			},
+		},
+		{
+			Version: "20250101000002",
+			Name:    "add_widget_index",
+			Up: func(tx *gorm.DB) error {
+				return tx.Exec("CREATE INDEX idx_widgets_name ON widgets (name)").Error
+			},
+			Down: func(tx *gorm.DB) error {
+				return tx.Exec("DROP INDEX idx_widgets_name").Error
+			},
+		},
+	}
+	return m
+}
+
+func TestUpDownRedo(t *testing.T) {
+	m := newTestMigrator(t)
+
+	done, err := m.Up(0)
+	require.NoError(t, err)
+	assert.Len(t, done, 2)
+	assert.True(t, m.db.Migrator().HasTable(&widget{}))
+
+	pending, err := m.Pending()
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+
+	done, err = m.Redo(1)
+	require.NoError(t, err)
+	assert.Equal(t, "20250101000002", done[0].Version)
+
+	done, err = m.Down(2)
+	require.NoError(t, err)
+	assert.Len(t, done, 2)
+	assert.Equal(t, "20250101000002", done[0].Version, "Down should roll back in reverse order")
+	assert.False(t, m.db.Migrator().HasTable(&widget{}))
+
+	statuses, err := m.Status()
+	require.NoError(t, err)
+	for _, status := range statuses {
+		assert.Nil(t, status.Applied)
+	}
+}
+
+func TestUpSteps(t *testing.T) {
+	m := newTestMigrator(t)
+
+	done, err := m.Up(1)
+	require.NoError(t, err)
+	assert.Len(t, done, 1)
+
+	applied, err := m.Applied()
+	require.NoError(t, err)
+	require.Len(t, applied, 1)
+	assert.Equal(t, 1, applied[0].Batch)
+
+	_, err = m.Up(0)
+	require.NoError(t, err)
+	applied, err = m.Applied()
+	require.NoError(t, err)
+	assert.Equal(t, 2, applied[1].Batch)
+}
+
+func TestDryRun(t *testing.T) {
+	m := newTestMigrator(t)
+	m.DryRun = true
+
+	done, err := m.Up(0)
+	require.NoError(t, err)
+	assert.Len(t, done, 2)
+	assert.NotEmpty(t, m.Statements)
+	assert.Contains(t, m.Statements[0], "CREATE TABLE")
+	assert.False(t, m.db.Migrator().HasTable(&widget{}), "Dry run should not touch the schema")
+	assert.False(t, m.db.Migrator().HasTable(&SchemaMigration{}))
+}
+
+func TestLockHeld(t *testing.T) {
+	m := newTestMigrator(t)
+	require.NoError(t, m.db.AutoMigrate(&SchemaMigration{}, &schemaMigrationLock{}))
+	require.NoError(t, m.db.Create(&schemaMigrationLock{ID: 1, Owner: "other", LockedAt: time.Now().Unix()}).Error)
+
+	_, err := m.Up(0)
+	assert.ErrorIs(t, err, ErrLocked)
+
+	// 过期的锁会被清理
+	m.LockTTL = -time.Second
+	_, err = m.Up(0)
+	assert.NoError(t, err)
+}
+
+func TestLockHeartbeat(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "lock.db")
+	open := func() *gorm.DB { return dbtest.OpenPath(t, path) }
+
+	started, release := make(chan struct{}), make(chan struct{})
+	first := New(open())
+	first.LockTTL = 300 * time.Millisecond
+	first.migrations = []*Migration{{
+		Version: "20250101000001",
+		Name:    "slow",
+		Up: func(tx *gorm.DB) error {
+			close(started)
+			<-release
+			return nil
+		},
+	}}
+	done := make(chan error, 1)
+	go func() {
+		_, err := first.Up(0)
+		done <- err
+	}()
+	<-started
+
+	// 迁移耗时超过 LockTTL，锁仍被续期而不会被其他进程清理
+	time.Sleep(1500 * time.Millisecond)
+	second := New(open())
+	second.LockWait = 0
+	second.LockTTL = first.LockTTL
+	second.migrations = nil
+	_, err := second.Up(0)
+	assert.ErrorIs(t, err, ErrLocked)
+
+	close(release)
+	require.NoError(t, <-done)
+	_, err = second.Up(0)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,30 @@
+package data
+
+import (
+	"fmt"
+	"insight/config/autoload"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func postgresDialector(cfg autoload.DatabaseConfig) gorm.Dialector {
+	sslMode := cfg.SSLMode
+	if sslMode == "" {
+		sslMode = "disable"
+	}
+	timeZone := cfg.TimeZone
+	if timeZone == "" {
+		timeZone = "Local"
+	}
+	dsn := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s TimeZone=%s",
+		cfg.Host,
+		cfg.Port,
+		cfg.Username,
+		cfg.Password,
+		cfg.Database,
+		sslMode,
+		timeZone,
+	)
+	return postgres.Open(dsn)
+}
@@ -0,0 +1,283 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"insight/config/autoload"
+	log "insight/internal/pkg/logger"
+	"math/rand/v2"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// resolverName 读写分离插件名称
+const resolverName = "insight:resolver"
+
+type replica struct {
+	name    string
+	db      *sql.DB
+	healthy atomic.Bool
+}
+
+// Policy 从健康的从库中选择一个处理读请求
+type Policy interface {
+	Pick(replicas []*replica) *replica
+}
+
+type randomPolicy struct{}
+
+func (randomPolicy) Pick(replicas []*replica) *replica {
+	return replicas[rand.IntN(len(replicas))]
+}
+
+type roundRobinPolicy struct {
+	next atomic.Uint64
+}
+
+func (p *roundRobinPolicy) Pick(replicas []*replica) *replica {
+	return replicas[(p.next.Add(1)-1)%uint64(len(replicas))]
+}
+
+// leastConnPolicy 选择正在使用连接数最少的从库
+type leastConnPolicy struct{}
+
+func (leastConnPolicy) Pick(replicas []*replica) *replica {
+	picked := replicas[0]
+	inUse := picked.db.Stats().InUse
+	for _, r := range replicas[1:] {
+		if n := r.db.Stats().InUse; n < inUse {
+			picked, inUse = r, n
+		}
+	}
+	return picked
+}
+
+func newPolicy(name string) (Policy, error) {
+	switch name {
+	case "", "round_robin":
+		return &roundRobinPolicy{}, nil
+	case "random":
+		return randomPolicy{}, nil
+	case "least_conn":
+		return leastConnPolicy{}, nil
+	}
+	return nil, fmt.Errorf("unsupported replica policy %q", name)
+}
+
+// resolver 是一个 GORM 插件，把事务外的读操作路由到健康的从库，写操作和事务始终使用主库
+type resolver struct {
+	primary  *sql.DB
+	replicas []*replica
+	policy   Policy
+	interval time.Duration
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+func newResolver(cfg autoload.DatabaseConfig) (*resolver, error) {
+	policy, err := newPolicy(cfg.ReplicaPolicy)
+	if err != nil {
+		return nil, err
+	}
+	interval := time.Duration(cfg.HealthCheckInterval) * time.Second
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+	r := &resolver{policy: policy, interval: interval, stop: make(chan struct{})}
+
+	names := make(map[string]bool, len(cfg.Replicas))
+	for i, replicaCfg := range cfg.Replicas {
+		merged := mergeReplicaConfig(cfg, replicaCfg)
+		conn, err := Open(merged)
+		if err != nil {
+			r.Close()
+			return nil, err
+		}
+		sqlDB, _ := conn.DB()
+		// 名称用于指标与健康检查，连接参数相同的从库追加序号区分
+		name := replicaName(merged)
+		if names[name] {
+			name = fmt.Sprintf("%s#%d", name, i)
+		}
+		names[name] = true
+		rep := &replica{name: name, db: sqlDB}
+		rep.healthy.Store(true)
+		r.replicas = append(r.replicas, rep)
+	}
+	return r, nil
+}
+
+// mergeReplicaConfig 从库未设置的连接参数沿用主库配置
+func mergeReplicaConfig(primary autoload.DatabaseConfig, replica autoload.ReplicaConfig) autoload.DatabaseConfig {
+	cfg := primary
+	cfg.Replicas = nil
+	if replica.Host != "" {
+		cfg.Host = replica.Host
+	}
+	if replica.Port != 0 {
+		cfg.Port = replica.Port
+	}
+	if replica.Username != "" {
+		cfg.Username = replica.Username
+	}
+	if replica.Password != "" {
+		cfg.Password = replica.Password
+	}
+	if replica.Database != "" {
+		cfg.Database = replica.Database
+	}
+	if replica.Path != "" {
+		cfg.Path = replica.Path
+	}
+	return cfg
+}
+
+// replicaName 按合并后的配置命名从库
+func replicaName(cfg autoload.DatabaseConfig) string {
+	if cfg.Driver == "sqlite" {
+		return cfg.Path
+	}
+	return fmt.Sprintf("%s:%d/%s", cfg.Host, cfg.Port, cfg.Database)
+}
+
+func (r *resolver) Name() string {
+	return resolverName
+}
+
+func (r *resolver) Initialize(db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	r.primary = sqlDB
+
+	callbacks := []error{
+		db.Callback().Query().Before("gorm:query").Register(resolverName+":query", r.routeRead),
+		db.Callback().Row().Before("gorm:row").Register(resolverName+":row", r.routeRead),
+		db.Callback().Create().After("gorm:create").Register(resolverName+":create", markWrite),
+		db.Callback().Update().After("gorm:update").Register(resolverName+":update", markWrite),
+		db.Callback().Delete().After("gorm:delete").Register(resolverName+":delete", markWrite),
+		db.Callback().Raw().After("gorm:raw").Register(resolverName+":raw", markWrite),
+	}
+	for _, err := range callbacks {
+		if err != nil {
+			return err
+		}
+	}
+
+	go r.healthCheck()
+	return nil
+}
+
+// routeRead 将可以走从库的读操作切换到从库连接
+func (r *resolver) routeRead(db *gorm.DB) {
+	stmt := db.Statement
+	if db.Error != nil || stmt.ConnPool != r.primary {
+		// 事务内或已显式指定连接
+		return
+	}
+	if _, locking := stmt.Clauses[clause.Locking{}.Name()]; locking {
+		return
+	}
+	if stmt.SQL.Len() > 0 && !isSelect(stmt.SQL.String()) {
+		return
+	}
+	if stmt.Context != nil && usePrimary(stmt.Context) {
+		return
+	}
+
+	if rep := r.pick(); rep != nil {
+		stmt.ConnPool = rep.db
+	}
+}
+
+func (r *resolver) pick() *replica {
+	healthy := make([]*replica, 0, len(r.replicas))
+	for _, rep := range r.replicas {
+		if rep.healthy.Load() {
+			healthy = append(healthy, rep)
+		}
+	}
+	if len(healthy) == 0 {
+		return nil
+	}
+	return r.policy.Pick(healthy)
+}
+
+// healthCheck 定期探测从库，失败的从库暂时摘除，恢复后重新加入
+func (r *resolver) healthCheck() {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+			for _, rep := range r.replicas {
+				ctx, cancel := context.WithTimeout(context.Background(), r.interval/2)
+				err := rep.db.PingContext(ctx)
+				cancel()
+
+				healthy := err == nil
+				if rep.healthy.Swap(healthy) != healthy {
+					if healthy {
+						log.Logger.Info("Database replica recovered", zap.String("replica", rep.name))
+					} else {
+						log.Logger.Warn("Database replica removed", zap.String("replica", rep.name), zap.Error(err))
+					}
+				}
+			}
+		}
+	}
+}
+
+// Close 停止健康检查并关闭全部从库连接
+func (r *resolver) Close() {
+	r.stopOnce.Do(func() { close(r.stop) })
+	for _, rep := range r.replicas {
+		_ = rep.db.Close()
+	}
+}
+
+func isSelect(sql string) bool {
+	sql = strings.TrimSpace(sql)
+	return len(sql) >= 6 && strings.EqualFold(sql[:6], "select")
+}
+
+type primaryKey struct{}
+
+type stickyKey struct{}
+
+// UsePrimary 返回的上下文中的读操作全部走主库
+func UsePrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+// WithReadYourWrites 返回的上下文一旦发生写操作，后续读操作全部走主库，避免读到从库延迟前的数据
+func WithReadYourWrites(ctx context.Context) context.Context {
+	return context.WithValue(ctx, stickyKey{}, new(atomic.Bool))
+}
+
+func usePrimary(ctx context.Context) bool {
+	if primary, _ := ctx.Value(primaryKey{}).(bool); primary {
+		return true
+	}
+	sticky, _ := ctx.Value(stickyKey{}).(*atomic.Bool)
+	return sticky != nil && sticky.Load()
+}
+
+func markWrite(db *gorm.DB) {
+	if db.Statement.Context == nil {
+		return
+	}
+	if sticky, _ := db.Statement.Context.Value(stickyKey{}).(*atomic.Bool); sticky != nil {
+		sticky.Store(true)
+	}
+}
@@ -0,0 +1,103 @@
+package data
+
+import (
+	"context"
+	"insight/config/autoload"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type node struct {
+	ID   uint `gorm:"primarykey"`
+	Name string
+}
+
+// newReplicatedDB 主库与从库分别写入不同的数据，通过查询结果判断读操作的路由
+func newReplicatedDB(t *testing.T) (*gorm.DB, *resolver) {
+	dir := t.TempDir()
+	cfg := autoload.DatabaseConfig{
+		Driver:   "sqlite",
+		Path:     filepath.Join(dir, "primary.db"),
+		LogLevel: "silent",
+		Replicas: []autoload.ReplicaConfig{{Path: filepath.Join(dir, "replica.db")}},
+	}
+
+	replicaDB, err := Open(mergeReplicaConfig(cfg, cfg.Replicas[0]))
+	require.NoError(t, err)
+	require.NoError(t, replicaDB.AutoMigrate(&node{}))
+	require.NoError(t, replicaDB.Create(&node{Name: "replica"}).Error)
+
+	primaryDB, err := Open(cfg)
+	require.NoError(t, err)
+	require.NoError(t, primaryDB.AutoMigrate(&node{}))
+	require.NoError(t, primaryDB.Create(&node{Name: "primary"}).Error)
+
+	r, err := newResolver(cfg)
+	require.NoError(t, err)
+	require.NoError(t, primaryDB.Use(r))
+	t.Cleanup(r.Close)
+	return primaryDB, r
+}
+
+func readName(db *gorm.DB) string {
+	var n node
+	db.First(&n)
+	return n.Name
+}
+
+func TestResolverRoutesReads(t *testing.T) {
+	db, r := newReplicatedDB(t)
+
+	assert.Equal(t, "replica", readName(db))
+	assert.Equal(t, "primary", readName(db.WithContext(UsePrimary(context.Background()))))
+
+	_ = db.Transaction(func(tx *gorm.DB) error {
+		assert.Equal(t, "primary", readName(tx), "Reads inside a transaction should use the primary")
+		return nil
+	})
+
+	r.replicas[0].healthy.Store(false)
+	assert.Equal(t, "primary", readName(db), "Reads should fall back to the primary without healthy replicas")
+}
+
+func TestResolverReadYourWrites(t *testing.T) {
+	db, _ := newReplicatedDB(t)
+	ctx := WithReadYourWrites(context.Background())
+
+	assert.Equal(t, "replica", readName(db.WithContext(ctx)))
+	require.NoError(t, db.WithContext(ctx).Create(&node{Name: "written"}).Error)
+	assert.Equal(t, "primary", readName(db.WithContext(ctx)), "Reads after a write should stick to the primary")
+
+	assert.Equal(t, "replica", readName(db.WithContext(context.Background())), "Other contexts are not affected")
+}
+
+func TestPolicies(t *testing.T) {
+	replicas := []*replica{{name: "a"}, {name: "b"}, {name: "c"}}
+
+	roundRobin := &roundRobinPolicy{}
+	assert.Equal(t, "a", roundRobin.Pick(replicas).name)
+	assert.Equal(t, "b", roundRobin.Pick(replicas).name)
+	assert.Equal(t, "c", roundRobin.Pick(replicas).name)
+	assert.Equal(t, "a", roundRobin.Pick(replicas).name)
+
+	_, err := newPolicy("fastest")
+	assert.Error(t, err)
+}
+
+func TestReplicaName(t *testing.T) {
+	primary := autoload.DatabaseConfig{Driver: "mysql", Host: "db", Port: 3306, Database: "app"}
+	// 未设置的连接参数沿用主库
+	assert.Equal(t, "db:3306/app", replicaName(mergeReplicaConfig(primary, autoload.ReplicaConfig{})))
+	assert.Equal(t, "replica:3307/app", replicaName(mergeReplicaConfig(primary, autoload.ReplicaConfig{Host: "replica", Port: 3307})))
+
+	path := filepath.Join(t.TempDir(), "primary.db")
+	r, err := newResolver(autoload.DatabaseConfig{Driver: "sqlite", Path: path, LogLevel: "silent", Replicas: []autoload.ReplicaConfig{{}, {}}})
+	require.NoError(t, err)
+	t.Cleanup(r.Close)
+	assert.Equal(t, path, r.replicas[0].name)
+	assert.Equal(t, path+"#1", r.replicas[1].name, "Replicas with the same connection should get distinct names")
+}
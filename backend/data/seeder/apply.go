@@ -0,0 +1,217 @@
+package seeder
+
+import (
+	"encoding/json"
+	"fmt"
+	"insight/internal/model"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+func seedPermissions(tx *gorm.DB, fixture *Fixture, result Result) error {
+	for _, item := range fixture.Permissions {
+		var permission model.Permission
+		if err := tx.Where("method = ? AND route = ?", item.Method, item.Route).Limit(1).Find(&permission).Error; err != nil {
+			return err
+		}
+		exists := permission.ID > 0
+
+		permission.Name = item.Name
+		permission.Desc = item.Desc
+		permission.Method = item.Method
+		permission.Route = item.Route
+		permission.Func = item.Func
+		permission.FuncPath = item.FuncPath
+		permission.IsAuth = item.IsAuth
+		permission.Sort = item.Sort
+		if err := save(tx, &permission, exists, "permissions", result); err != nil {
+			return fmt.Errorf("seed permission %s %s: %w", item.Method, item.Route, err)
+		}
+	}
+	return nil
+}
+
+func seedRoles(tx *gorm.DB, fixture *Fixture, result Result) error {
+	for _, item := range fixture.Roles {
+		var role model.Role
+		if err := tx.Where("code = ?", item.Code).Limit(1).Find(&role).Error; err != nil {
+			return err
+		}
+		exists := role.ID > 0
+
+		role.Code = item.Code
+		role.Name = item.Name
+		role.Desc = item.Desc
+		role.Status = statusOrDefault(item.Status)
+		role.Sort = item.Sort
+		if err := save(tx, &role, exists, "roles", result); err != nil {
+			return fmt.Errorf("seed role %s: %w", item.Code, err)
+		}
+		if err := syncRolePermissions(tx, &role, item.Permissions); err != nil {
+			return fmt.Errorf("seed role %s: %w", item.Code, err)
+		}
+	}
+	return nil
+}
+
+// syncRolePermissions 将角色权限替换为 permissions 对应的权限
+func syncRolePermissions(tx *gorm.DB, role *model.Role, permissions []string) error {
+	var permissionIDs []uint
+	if len(permissions) == 1 && permissions[0] == "*" {
+		if err := tx.Model(&model.Permission{}).Pluck("id", &permissionIDs).Error; err != nil {
+			return err
+		}
+	} else {
+		for _, item := range permissions {
+			method, route, ok := strings.Cut(item, " ")
+			if !ok {
+				return fmt.Errorf("permission %q must be in \"METHOD /route\" format", item)
+			}
+			var permission model.Permission
+			if err := tx.Where("method = ? AND route = ?", method, route).Limit(1).Find(&permission).Error; err != nil {
+				return err
+			}
+			if permission.ID == 0 {
+				return fmt.Errorf("unknown permission %q", item)
+			}
+			permissionIDs = append(permissionIDs, permission.ID)
+		}
+	}
+
+	if err := tx.Where("role_id = ?", role.ID).Delete(&model.RolePermission{}).Error; err != nil {
+		return err
+	}
+	if len(permissionIDs) == 0 {
+		return nil
+	}
+	rolePermissions := make([]model.RolePermission, 0, len(permissionIDs))
+	for _, id := range permissionIDs {
+		rolePermissions = append(rolePermissions, model.RolePermission{RoleID: role.ID, PermissionID: id})
+	}
+	return tx.Create(&rolePermissions).Error
+}
+
+func seedAdminUsers(tx *gorm.DB, fixture *Fixture, result Result) error {
+	for _, item := range fixture.AdminUsers {
+		var user model.AdminUser
+		if err := tx.Where("username = ?", item.Username).Limit(1).Find(&user).Error; err != nil {
+			return err
+		}
+		exists := user.ID > 0
+
+		if !exists {
+			if item.Password == "" {
+				return fmt.Errorf("seed admin user %s: password is required", item.Username)
+			}
+			hash, err := user.PasswordHash(item.Password)
+			if err != nil {
+				return err
+			}
+			user.Password = hash
+		}
+
+		var count int64
+		if err := tx.Model(&model.Role{}).Where("code IN ?", item.Roles).Count(&count).Error; err != nil {
+			return err
+		}
+		if int(count) != len(item.Roles) {
+			return fmt.Errorf("seed admin user %s: unknown role in %v", item.Username, item.Roles)
+		}
+		roles := item.Roles
+		if roles == nil {
+			roles = []string{}
+		}
+		rolesJSON, _ := json.Marshal(roles)
+
+		user.Username = item.Username
+		user.NickName = item.NickName
+		if user.NickName == "" {
+			user.NickName = item.Username
+		}
+		user.Email = item.Email
+		user.Mobile = item.Mobile
+		user.Avatar = item.Avatar
+		user.IsAdmin = item.IsAdmin
+		user.Status = statusOrDefault(item.Status)
+		user.Roles = rolesJSON
+		if err := save(tx, &user, exists, "admin_users", result); err != nil {
+			return fmt.Errorf("seed admin user %s: %w", item.Username, err)
+		}
+	}
+	return nil
+}
+
+func seedMenus(tx *gorm.DB, fixture *Fixture, result Result) error {
+	for _, item := range fixture.Menus {
+		var parentID uint
+		if item.Parent != "" {
+			var parent model.Menu
+			if err := tx.Where("name = ?", item.Parent).Limit(1).Find(&parent).Error; err != nil {
+				return err
+			}
+			if parent.ID == 0 {
+				return fmt.Errorf("seed menu %s: parent %s must be seeded first", item.Name, item.Parent)
+			}
+			parentID = parent.ID
+		}
+
+		var menu model.Menu
+		if err := tx.Where("name = ?", item.Name).Limit(1).Find(&menu).Error; err != nil {
+			return err
+		}
+		exists := menu.ID > 0
+
+		menu.ParentID = parentID
+		menu.Name = item.Name
+		menu.Title = item.Title
+		menu.Path = item.Path
+		menu.Component = item.Component
+		menu.Icon = item.Icon
+		menu.Status = statusOrDefault(item.Status)
+		menu.Sort = item.Sort
+		if err := save(tx, &menu, exists, "menus", result); err != nil {
+			return fmt.Errorf("seed menu %s: %w", item.Name, err)
+		}
+	}
+	return nil
+}
+
+func seedDictionaries(tx *gorm.DB, fixture *Fixture, result Result) error {
+	for _, item := range fixture.Dictionaries {
+		var dictionary model.Dictionary
+		if err := tx.Where("type = ? AND value = ?", item.Type, item.Value).Limit(1).Find(&dictionary).Error; err != nil {
+			return err
+		}
+		exists := dictionary.ID > 0
+
+		dictionary.Type = item.Type
+		dictionary.Label = item.Label
+		dictionary.Value = item.Value
+		dictionary.Remark = item.Remark
+		dictionary.Status = statusOrDefault(item.Status)
+		dictionary.Sort = item.Sort
+		if err := save(tx, &dictionary, exists, "dictionaries", result); err != nil {
+			return fmt.Errorf("seed dictionary %s.%s: %w", item.Type, item.Value, err)
+		}
+	}
+	return nil
+}
+
+func save(tx *gorm.DB, value any, exists bool, kind string, result Result) error {
+	if !exists {
+		if err := tx.Create(value).Error; err != nil {
+			return err
+		}
+		result.created(kind)
+		return nil
+	}
+	if v, ok := value.(interface{ NextVersion() }); ok {
+		v.NextVersion()
+	}
+	if err := tx.Save(value).Error; err != nil {
+		return err
+	}
+	result.updated(kind)
+	return nil
+}
@@ -0,0 +1,84 @@
+package seeder
+
+// Fixture 一个种子文件的内容，各部分均可省略
+type Fixture struct {
+	Permissions  []PermissionFixture `yaml:"permissions" json:"permissions"`
+	Roles        []RoleFixture       `yaml:"roles" json:"roles"`
+	AdminUsers   []AdminUserFixture  `yaml:"admin_users" json:"admin_users"`
+	Menus        []MenuFixture       `yaml:"menus" json:"menus"`
+	Dictionaries []DictionaryFixture `yaml:"dictionaries" json:"dictionaries"`
+}
+
+// merge 追加另一个种子文件的内容
+func (f *Fixture) merge(other *Fixture) {
+	f.Permissions = append(f.Permissions, other.Permissions...)
+	f.Roles = append(f.Roles, other.Roles...)
+	f.AdminUsers = append(f.AdminUsers, other.AdminUsers...)
+	f.Menus = append(f.Menus, other.Menus...)
+	f.Dictionaries = append(f.Dictionaries, other.Dictionaries...)
+}
+
+// PermissionFixture 权限，以 method + route 为唯一标识
+type PermissionFixture struct {
+	Name     string `yaml:"name" json:"name"`
+	Desc     string `yaml:"desc" json:"desc"`
+	Method   string `yaml:"method" json:"method"`
+	Route    string `yaml:"route" json:"route"`
+	Func     string `yaml:"func" json:"func"`
+	FuncPath string `yaml:"func_path" json:"func_path"`
+	IsAuth   int8   `yaml:"is_auth" json:"is_auth"`
+	Sort     int32  `yaml:"sort" json:"sort"`
+}
+
+// RoleFixture 角色，以 code 为唯一标识；permissions 为 "METHOD /route" 格式的权限列表，"*" 表示全部权限
+type RoleFixture struct {
+	Code        string   `yaml:"code" json:"code"`
+	Name        string   `yaml:"name" json:"name"`
+	Desc        string   `yaml:"desc" json:"desc"`
+	Status      *int8    `yaml:"status" json:"status"`
+	Sort        int32    `yaml:"sort" json:"sort"`
+	Permissions []string `yaml:"permissions" json:"permissions"`
+}
+
+// AdminUserFixture 管理员，以 username 为唯一标识；密码只在创建时写入
+type AdminUserFixture struct {
+	Username string   `yaml:"username" json:"username"`
+	Password string   `yaml:"password" json:"password"`
+	NickName string   `yaml:"nickname" json:"nickname"`
+	Email    string   `yaml:"email" json:"email"`
+	Mobile   string   `yaml:"mobile" json:"mobile"`
+	Avatar   string   `yaml:"avatar" json:"avatar"`
+	IsAdmin  int8     `yaml:"is_admin" json:"is_admin"`
+	Status   *int8    `yaml:"status" json:"status"`
+	Roles    []string `yaml:"roles" json:"roles"`
+}
+
+// MenuFixture 菜单，以 name 为唯一标识；parent 为父级菜单的 name
+type MenuFixture struct {
+	Name      string `yaml:"name" json:"name"`
+	Parent    string `yaml:"parent" json:"parent"`
+	Title     string `yaml:"title" json:"title"`
+	Path      string `yaml:"path" json:"path"`
+	Component string `yaml:"component" json:"component"`
+	Icon      string `yaml:"icon" json:"icon"`
+	Status    *int8  `yaml:"status" json:"status"`
+	Sort      int32  `yaml:"sort" json:"sort"`
+}
+
+// DictionaryFixture 数据字典，以 type + value 为唯一标识
+type DictionaryFixture struct {
+	Type   string `yaml:"type" json:"type"`
+	Label  string `yaml:"label" json:"label"`
+	Value  string `yaml:"value" json:"value"`
+	Remark string `yaml:"remark" json:"remark"`
+	Status *int8  `yaml:"status" json:"status"`
+	Sort   int32  `yaml:"sort" json:"sort"`
+}
+
+// statusOrDefault 未设置状态时默认启用
+func statusOrDefault(status *int8) int8 {
+	if status == nil {
+		return 1
+	}
+	return *status
+}
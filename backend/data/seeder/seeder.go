@@ -0,0 +1,166 @@
+package seeder
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"insight/internal/model"
+	"io/fs"
+	"path"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+	"gorm.io/gorm"
+)
+
+// BaseSet 所有环境都会加载的种子集
+const BaseSet = "base"
+
+// Count 单类数据的写入统计
+type Count struct {
+	Created int
+	Updated int
+}
+
+// Result 按数据类型统计的写入结果
+type Result map[string]*Count
+
+func (r Result) created(kind string) {
+	r.count(kind).Created++
+}
+
+func (r Result) updated(kind string) {
+	r.count(kind).Updated++
+}
+
+func (r Result) count(kind string) *Count {
+	if _, ok := r[kind]; !ok {
+		r[kind] = &Count{}
+	}
+	return r[kind]
+}
+
+// Kinds 返回有写入记录的数据类型，按名称排序
+func (r Result) Kinds() []string {
+	kinds := make([]string, 0, len(r))
+	for kind := range r {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+type Seeder struct {
+	db   *gorm.DB
+	fsys fs.FS
+}
+
+// New 创建 Seeder，fsys 的一级目录为种子集（base、dev、demo、test 等）
+func New(db *gorm.DB, fsys fs.FS) *Seeder {
+	return &Seeder{db: db, fsys: fsys}
+}
+
+// Load 依次读取 base 与 env 目录下的 yaml/json 文件并合并，同一目录内按文件名排序
+func (s *Seeder) Load(env string) (*Fixture, error) {
+	fixture := &Fixture{}
+	sets := []string{BaseSet}
+	if env != "" && env != BaseSet {
+		sets = append(sets, env)
+	}
+
+	for _, set := range sets {
+		entries, err := fs.ReadDir(s.fsys, set)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) && set == BaseSet {
+				continue
+			}
+			return nil, fmt.Errorf("read seed set %q: %w", set, err)
+		}
+		for _, entry := range entries {
+			if entry.IsDir() {
+				continue
+			}
+			name := path.Join(set, entry.Name())
+			f, err := s.parse(name)
+			if err != nil {
+				return nil, fmt.Errorf("parse seed file %s: %w", name, err)
+			}
+			if f != nil {
+				fixture.merge(f)
+			}
+		}
+	}
+	return fixture, nil
+}
+
+func (s *Seeder) parse(name string) (*Fixture, error) {
+	var unmarshal func([]byte, any) error
+	switch path.Ext(name) {
+	case ".yaml", ".yml":
+		unmarshal = yaml.Unmarshal
+	case ".json":
+		unmarshal = json.Unmarshal
+	default:
+		return nil, nil
+	}
+
+	content, err := fs.ReadFile(s.fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	fixture := &Fixture{}
+	if err := unmarshal(content, fixture); err != nil {
+		return nil, err
+	}
+	return fixture, nil
+}
+
+// Seed 在一个事务中写入 env 对应的种子数据，已存在的数据按唯一标识更新
+func (s *Seeder) Seed(env string) (Result, error) {
+	fixture, err := s.Load(env)
+	if err != nil {
+		return nil, err
+	}
+	return s.Apply(fixture)
+}
+
+// Apply 在一个事务中写入 fixture，测试可直接传入代码中构造的数据
+func (s *Seeder) Apply(fixture *Fixture) (Result, error) {
+	result := Result{}
+	steps := []func(tx *gorm.DB, fixture *Fixture, result Result) error{
+		seedPermissions,
+		seedRoles,
+		seedAdminUsers,
+		seedMenus,
+		seedDictionaries,
+	}
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		for _, step := range steps {
+			if err := step(tx, fixture, result); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return result, err
+}
+
+// Truncate 清空种子涉及的全部表，包括已软删除的数据
+func (s *Seeder) Truncate() error {
+	tables := []any{
+		&model.RolePermission{},
+		&model.Dictionary{},
+		&model.Menu{},
+		&model.AdminUser{},
+		&model.Role{},
+		&model.Permission{},
+	}
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		for _, table := range tables {
+			if err := tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Unscoped().Delete(table).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
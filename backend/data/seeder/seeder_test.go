@@ -0,0 +1,89 @@
+package seeder
+
+import (
+	"insight/data/dbtest"
+	_ "insight/data/migrations"
+	"insight/data/migrator"
+	"insight/data/seeds"
+	"insight/internal/model"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func newTestDB(t *testing.T) *gorm.DB {
+	db := dbtest.Open(t)
+	_, err := migrator.New(db).Up(0)
+	require.NoError(t, err)
+	return db
+}
+
+func TestSeedIsIdempotent(t *testing.T) {
+	db := newTestDB(t)
+	s := New(db, seeds.FS)
+
+	result, err := s.Seed("test")
+	require.NoError(t, err)
+	assert.Equal(t, 2, result["admin_users"].Created)
+	assert.Equal(t, 0, result["admin_users"].Updated)
+
+	result, err = s.Seed("test")
+	require.NoError(t, err)
+	assert.Equal(t, 0, result["admin_users"].Created)
+	assert.Equal(t, 2, result["admin_users"].Updated)
+
+	var users, roles, rolePermissions int64
+	db.Model(&model.AdminUser{}).Count(&users)
+	db.Model(&model.Role{}).Count(&roles)
+	db.Model(&model.RolePermission{}).Count(&rolePermissions)
+	assert.EqualValues(t, 2, users)
+	assert.EqualValues(t, 3, roles)
+	assert.NotZero(t, rolePermissions)
+
+	var admin model.AdminUser
+	require.NoError(t, db.Where("username = ?", "admin").First(&admin).Error)
+	assert.True(t, admin.ComparePasswords("admin123"))
+	assert.JSONEq(t, `["super_admin"]`, string(admin.Roles))
+
+	var child model.Menu
+	require.NoError(t, db.Where("name = ?", "system_user").First(&child).Error)
+	assert.NotZero(t, child.ParentID)
+}
+
+func TestSeedKeepsExistingPassword(t *testing.T) {
+	db := newTestDB(t)
+	s := New(db, seeds.FS)
+
+	_, err := s.Seed("test")
+	require.NoError(t, err)
+
+	admin := model.AdminUser{}
+	require.NoError(t, db.Where("username = ?", "admin").First(&admin).Error)
+	hash, _ := admin.PasswordHash("changed")
+	require.NoError(t, db.Model(&admin).Update("password", hash).Error)
+
+	_, err = s.Seed("test")
+	require.NoError(t, err)
+	require.NoError(t, db.Where("username = ?", "admin").First(&admin).Error)
+	assert.True(t, admin.ComparePasswords("changed"))
+}
+
+func TestTruncate(t *testing.T) {
+	db := newTestDB(t)
+	s := New(db, seeds.FS)
+
+	_, err := s.Seed("test")
+	require.NoError(t, err)
+	require.NoError(t, s.Truncate())
+
+	var permissions int64
+	db.Unscoped().Model(&model.Permission{}).Count(&permissions)
+	assert.Zero(t, permissions)
+}
+
+func TestLoadUnknownSet(t *testing.T) {
+	_, err := New(nil, seeds.FS).Load("staging")
+	assert.Error(t, err)
+}
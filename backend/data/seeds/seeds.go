@@ -0,0 +1,10 @@
+// Package seeds 内置的种子数据。
+//
+// base 目录在所有环境下加载，dev、demo、test 目录按 `insight seed --env` 选择加载，
+// 同一目录内的文件按文件名顺序处理，支持 yaml 与 json 格式。
+package seeds
+
+import "embed"
+
+//go:embed base dev demo test
+var FS embed.FS
@@ -0,0 +1,35 @@
+package data
+
+import (
+	"insight/config/autoload"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+// sqliteDialector 使用纯 Go 实现的 SQLite 驱动，无需 CGO
+func sqliteDialector(cfg autoload.DatabaseConfig) gorm.Dialector {
+	path := cfg.Path
+	if path == "" {
+		path = filepath.Join("storage", "insight.db")
+	}
+	if isSqliteMemory(path) {
+		if !strings.HasPrefix(path, "file:") || strings.HasPrefix(path, "file::memory:") {
+			return sqlite.Open("file::memory:?cache=shared&_pragma=foreign_keys(1)")
+		}
+		// 命名内存库 file:<name>?mode=memory，不同名称互相隔离，供测试使用
+		return sqlite.Open(path + "&cache=shared&_pragma=foreign_keys(1)")
+	}
+	if dir := filepath.Dir(path); dir != "." {
+		_ = os.MkdirAll(dir, 0o755)
+	}
+	return sqlite.Open(path + "?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_pragma=foreign_keys(1)")
+}
+
+func isSqliteMemory(path string) bool {
+	return path == ":memory:" || strings.HasPrefix(path, "file::memory:") ||
+		strings.HasPrefix(path, "file:") && strings.Contains(path, "mode=memory")
+}
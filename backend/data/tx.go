@@ -0,0 +1,36 @@
+package data
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type (
+	txKey struct{}
+	dbKey struct{}
+)
+
+// WithDB 将数据库连接绑定到上下文，FromContext 优先使用该连接而不是全局连接，测试可借此注入独立的数据库
+func WithDB(ctx context.Context, db *gorm.DB) context.Context {
+	return context.WithValue(ctx, dbKey{}, db)
+}
+
+// Transaction 在事务中执行 fn，fn 收到的上下文携带该事务，通过 FromContext 取得的连接都会加入事务。
+// 上下文已处于事务中时使用保存点嵌套执行。
+func Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return FromContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
+
+// FromContext 返回上下文中的事务，不在事务中时返回 WithDB 绑定或全局的数据库连接
+func FromContext(ctx context.Context) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+	if db, ok := ctx.Value(dbKey{}).(*gorm.DB); ok {
+		return db.WithContext(ctx)
+	}
+	return DB().WithContext(ctx)
+}
@@ -0,0 +1,82 @@
+package data_test
+
+import (
+	"context"
+	"errors"
+	"insight/data"
+	"insight/data/dbtest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type node struct {
+	ID   uint `gorm:"primarykey"`
+	Name string
+}
+
+func useTestDB(t *testing.T) {
+	conn := dbtest.Open(t)
+	require.NoError(t, conn.AutoMigrate(&node{}))
+	t.Cleanup(data.SetDB(conn))
+}
+
+func countNodes(ctx context.Context) (count int64) {
+	data.FromContext(ctx).Model(&node{}).Count(&count)
+	return
+}
+
+func TestTransaction(t *testing.T) {
+	useTestDB(t)
+	ctx := context.Background()
+
+	err := data.Transaction(ctx, func(ctx context.Context) error {
+		require.NoError(t, data.FromContext(ctx).Create(&node{Name: "a"}).Error)
+		assert.EqualValues(t, 1, countNodes(ctx), "Writes should be visible inside the transaction")
+		return errors.New("rollback")
+	})
+	assert.EqualError(t, err, "rollback")
+	assert.Zero(t, countNodes(ctx))
+
+	err = data.Transaction(ctx, func(ctx context.Context) error {
+		require.NoError(t, data.FromContext(ctx).Create(&node{Name: "b"}).Error)
+		// 嵌套事务失败只回滚到保存点
+		_ = data.Transaction(ctx, func(ctx context.Context) error {
+			require.NoError(t, data.FromContext(ctx).Create(&node{Name: "c"}).Error)
+			return errors.New("rollback inner")
+		})
+		return nil
+	})
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, countNodes(ctx))
+}
+
+func TestFromContextCancelled(t *testing.T) {
+	useTestDB(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := data.FromContext(ctx).Create(&node{Name: "a"}).Error
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestWithDB(t *testing.T) {
+	useTestDB(t)
+	ctx := context.Background()
+	require.NoError(t, data.FromContext(ctx).Create(&node{Name: "global"}).Error)
+
+	// dbtest 打开的内存库互相隔离
+	conn := dbtest.Open(t)
+	require.NoError(t, conn.AutoMigrate(&node{}))
+	bound := data.WithDB(ctx, conn)
+	assert.Zero(t, countNodes(bound), "Bound connection should be used instead of the global one")
+
+	// 事务基于绑定的连接开启
+	err := data.Transaction(bound, func(ctx context.Context) error {
+		return data.FromContext(ctx).Create(&node{Name: "bound"}).Error
+	})
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, countNodes(bound))
+	assert.EqualValues(t, 1, countNodes(ctx))
+}
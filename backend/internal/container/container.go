@@ -0,0 +1,169 @@
+package container
+
+import (
+	"context"
+	"insight/config"
+	"insight/data"
+	"insight/internal/pkg/cache"
+	"insight/internal/pkg/clock"
+	log "insight/internal/pkg/logger"
+	"insight/internal/service"
+	"insight/internal/service/admin_auth"
+	"sync"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// Container 应用容器，统一构造配置、日志、数据库、缓存与服务，并注入控制器和命令。
+// 依赖在首次使用时构造并复用，命令可以先在 PreRun 中初始化数据库与缓存再使用容器；测试通过 Option 替换为假实现。
+type Container struct {
+	config lazy[*config.Config]
+	logger lazy[*zap.Logger]
+	db     lazy[*gorm.DB]
+	cache  lazy[cache.Store]
+	clock  lazy[clock.Clock]
+
+	hello      lazy[service.HelloService]
+	demo       lazy[service.DemoService]
+	routeHit   lazy[*service.RouteHitService]
+	adminUser  lazy[*admin_auth.AdminUserService]
+	login      lazy[*admin_auth.LoginService]
+	permission lazy[*admin_auth.PermissionService]
+	trash      lazy[*admin_auth.TrashService]
+}
+
+type Option func(*Container)
+
+// WithConfig 使用指定配置而不是读取配置文件
+func WithConfig(cfg *config.Config) Option {
+	return func(c *Container) { c.config.set(cfg) }
+}
+
+// WithLogger 使用指定日志对象而不是全局 Logger
+func WithLogger(logger *zap.Logger) Option {
+	return func(c *Container) { c.logger.set(logger) }
+}
+
+// WithDB 使用指定数据库连接而不是 data.InitData 初始化的全局连接
+func WithDB(db *gorm.DB) Option {
+	return func(c *Container) { c.db.set(db) }
+}
+
+// WithCache 使用指定缓存而不是 cache.InitCache 初始化的默认缓存
+func WithCache(store cache.Store) Option {
+	return func(c *Container) { c.cache.set(store) }
+}
+
+// WithClock 使用指定时钟而不是系统时钟
+func WithClock(clk clock.Clock) Option {
+	return func(c *Container) { c.clock.set(clk) }
+}
+
+// WithHelloService 替换 Hello 服务
+func WithHelloService(hello service.HelloService) Option {
+	return func(c *Container) { c.hello.set(hello) }
+}
+
+// WithDemoService 替换 Demo 服务
+func WithDemoService(demo service.DemoService) Option {
+	return func(c *Container) { c.demo.set(demo) }
+}
+
+// New 创建容器，未通过 Option 指定的依赖使用全局初始化的实例
+func New(opts ...Option) *Container {
+	c := &Container{}
+	c.config.build = config.GetConfig
+	c.logger.build = func() *zap.Logger { return log.Logger }
+	c.db.build = data.DB
+	c.cache.build = cache.Instance
+	c.clock.build = func() clock.Clock { return clock.Real }
+
+	c.hello.build = service.NewHelloService
+	c.demo.build = func() service.DemoService { return service.NewDemoService(c.Logger()) }
+	c.routeHit.build = func() *service.RouteHitService { return service.NewRouteHitService(c.Logger(), c.Clock()) }
+	c.adminUser.build = func() *admin_auth.AdminUserService {
+		return admin_auth.NewAdminUserService(c.Logger(), c.Cache())
+	}
+	c.login.build = func() *admin_auth.LoginService {
+		return admin_auth.NewLoginService(c.Logger(), c.Config().Jwt, c.Clock())
+	}
+	c.permission.build = func() *admin_auth.PermissionService { return admin_auth.NewPermissionService(c.Logger()) }
+	c.trash.build = func() *admin_auth.TrashService {
+		return admin_auth.NewTrashService(c.Logger(), c.AdminUserService())
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+func (c *Container) Config() *config.Config { return c.config.get() }
+
+func (c *Container) Logger() *zap.Logger { return c.logger.get() }
+
+// DB 返回数据库连接，未开启数据库时为 nil
+func (c *Container) DB() *gorm.DB { return c.db.get() }
+
+// Cache 返回缓存，命令未初始化缓存时为 nil
+func (c *Container) Cache() cache.Store { return c.cache.get() }
+
+func (c *Container) Clock() clock.Clock { return c.clock.get() }
+
+func (c *Container) HelloService() service.HelloService { return c.hello.get() }
+
+func (c *Container) DemoService() service.DemoService { return c.demo.get() }
+
+func (c *Container) RouteHitService() *service.RouteHitService { return c.routeHit.get() }
+
+func (c *Container) AdminUserService() *admin_auth.AdminUserService { return c.adminUser.get() }
+
+func (c *Container) LoginService() *admin_auth.LoginService { return c.login.get() }
+
+func (c *Container) PermissionService() *admin_auth.PermissionService { return c.permission.get() }
+
+func (c *Container) TrashService() *admin_auth.TrashService { return c.trash.get() }
+
+// Context 将容器的数据库连接绑定到 ctx，模型通过 ctx 使用该连接
+func (c *Container) Context(ctx context.Context) context.Context {
+	if db := c.DB(); db != nil {
+		return data.WithDB(ctx, db)
+	}
+	return ctx
+}
+
+// lazy 首次 get 时调用 build 构造实例，之后返回同一实例
+type lazy[T any] struct {
+	once  sync.Once
+	build func() T
+	value T
+}
+
+func (l *lazy[T]) get() T {
+	l.once.Do(func() { l.value = l.build() })
+	return l.value
+}
+
+// set 使用固定实例，需在首次 get 之前调用
+func (l *lazy[T]) set(value T) {
+	l.build = func() T { return value }
+}
+
+type ctxKey struct{}
+
+// NewContext 将容器存入 ctx，命令通过 cmd.Context() 取得
+func NewContext(ctx context.Context, c *Container) context.Context {
+	return context.WithValue(ctx, ctxKey{}, c)
+}
+
+// FromContext 返回 ctx 中的容器，不存在时返回进程共享的默认容器
+func FromContext(ctx context.Context) *Container {
+	if c, ok := ctx.Value(ctxKey{}).(*Container); ok {
+		return c
+	}
+	return Default()
+}
+
+// Default 进程共享的默认容器
+var Default = sync.OnceValue(func() *Container { return New() })
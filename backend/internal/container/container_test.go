@@ -0,0 +1,64 @@
+package container
+
+import (
+	"context"
+	"insight/config"
+	"insight/config/autoload"
+	"insight/data"
+	"insight/data/dbtest"
+	"insight/internal/pkg/cache"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type fakeHello struct{}
+
+func (fakeHello) Hello() (string, error) { return "fake", nil }
+
+func TestOptions(t *testing.T) {
+	cfg := &config.Config{Jwt: autoload.JwtConfig{HeaderPrefix: "Bearer"}}
+	logger := zap.NewNop()
+	store := cache.NewMemory(0, 0)
+	c := New(WithConfig(cfg), WithLogger(logger), WithCache(store), WithHelloService(fakeHello{}))
+
+	assert.Same(t, cfg, c.Config())
+	assert.Same(t, logger, c.Logger())
+	assert.Equal(t, store, c.Cache())
+	result, err := c.HelloService().Hello()
+	require.NoError(t, err)
+	assert.Equal(t, "fake", result)
+
+	// 服务只构造一次，依赖它的服务共享同一实例
+	assert.Same(t, c.AdminUserService(), c.AdminUserService())
+	assert.NotNil(t, c.TrashService())
+	assert.NotNil(t, c.LoginService())
+}
+
+func TestLazy(t *testing.T) {
+	builds := 0
+	c := New(WithConfig(&config.Config{}))
+	c.logger.build = func() *zap.Logger {
+		builds++
+		return zap.NewNop()
+	}
+	assert.Zero(t, builds, "Dependencies should not be built before first use")
+	c.PermissionService()
+	c.RouteHitService()
+	assert.Equal(t, 1, builds)
+}
+
+func TestContext(t *testing.T) {
+	ctx := context.Background()
+	assert.Equal(t, ctx, New(WithDB(nil)).Context(ctx), "Nil database should leave the context unchanged")
+
+	db := dbtest.Open(t)
+	bound := New(WithDB(db)).Context(ctx)
+	assert.Same(t, db.Statement.ConnPool, data.FromContext(bound).Statement.ConnPool)
+
+	assert.Same(t, Default(), FromContext(ctx))
+	c := New()
+	assert.Same(t, c, FromContext(NewContext(ctx, c)))
+}
@@ -0,0 +1,150 @@
+package admin_test
+
+import (
+	"errors"
+	"fmt"
+	"insight/config"
+	"insight/internal/model"
+	e "insight/internal/pkg/errors"
+	"insight/internal/testutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/plugin/soft_delete"
+)
+
+func TestLogin(t *testing.T) {
+	h := testutil.New(t)
+	login := func(username, password string) *testutil.Response {
+		return h.POST("/api/v1/admin/login/").JSON(map[string]string{"username": username, "password": password}).Do()
+	}
+
+	login("admin", "admin123").OK().Field("token_type", "Bearer")
+	login("admin", "wrong-password").Fail(e.FAILURE).Message("用户密码错误")
+	login("nobody", "password").Fail(e.UserDoesNotExist)
+	login("adm", "").Fail(e.InvalidParameter)
+}
+
+func TestUserList(t *testing.T) {
+	h := testutil.New(t)
+
+	h.GET("/api/v1/admin/users/").As("admin").Query("sort", "username").Do().OK().
+		Field("total", 2).
+		Field("Data.0.username", "admin").
+		Field("Data.1.username", "viewer")
+	h.GET("/api/v1/admin/users/").As("admin").Query("filter", "username:eq:viewer").Query("fields", "username").Do().OK().
+		Field("total", 1).
+		Field("Data.0", map[string]string{"username": "viewer"})
+	h.GET("/api/v1/admin/users/").As("admin").Query("filter", "password:eq:x").Do().Fail(e.InvalidParameter)
+}
+
+func TestUserUpdate(t *testing.T) {
+	h := testutil.New(t)
+	info := h.GET("/api/v1/admin/users/info").As("viewer").Do().OK()
+	var user struct {
+		ID      uint `json:"userId"`
+		Version uint `json:"version"`
+	}
+	info.Data(&user)
+	etag := info.Recorder.Header().Get("ETag")
+
+	h.PUT("/api/v1/admin/users/").As("viewer").Header("If-Match", etag).
+		JSON(map[string]any{"id": user.ID, "nickname": "新昵称"}).
+		Do().OK()
+	h.GET("/api/v1/admin/users/info").As("viewer").Do().OK().Field("realName", "新昵称")
+
+	// 使用过期的版本号
+	h.PUT("/api/v1/admin/users/").As("viewer").Header("If-Match", etag).
+		JSON(map[string]any{"id": user.ID, "nickname": "旧昵称"}).
+		Do().Status(http.StatusPreconditionFailed).Code(e.Conflict).Field("realName", "新昵称")
+	h.PUT("/api/v1/admin/users/").As("viewer").
+		JSON(map[string]any{"id": user.ID, "version": user.Version, "nickname": "旧昵称"}).
+		Do().Fail(e.Conflict)
+}
+
+func TestPermissionList(t *testing.T) {
+	h := testutil.New(t)
+	h.GET("/api/v1/admin/permissions/").Do().Fail(e.NotLogin)
+	h.GET("/api/v1/admin/permissions/1").Do().Fail(e.NotLogin)
+	h.GET("/api/v1/admin/permissions/").As("viewer").Query("per_page", "2").Do().OK().
+		Field("total", 5).
+		Field("Data.0.route", "/api/admin/users/info").
+		Field("last_page", 3)
+}
+
+func TestTrashPurge(t *testing.T) {
+	h := testutil.New(t)
+	viewer := model.NewAdminUsers().GetUserInfo(h.Context(), "viewer")
+	require.NotNil(t, viewer)
+	require.NoError(t, h.DB.Delete(viewer).Error)
+	ids := map[string][]uint{"ids": {viewer.ID}}
+
+	h.GET("/api/v1/admin/trash/admin_users/").As("admin").Do().OK().Field("total", 1)
+	// 永久删除仅限超级管理员
+	h.DELETE("/api/v1/admin/trash/admin_users/").Token(h.TokenWithRoles("admin")).JSON(ids).Do().Fail(e.AuthorizationError)
+	h.DELETE("/api/v1/admin/trash/admin_users/").As("admin").JSON(ids).Do().OK().Field("purged", 1)
+	h.GET("/api/v1/admin/trash/admin_users/").As("admin").Do().OK().Field("total", 0)
+}
+
+// failAdminUserQueries 让后台用户表的查询失败，返回恢复函数
+func failAdminUserQueries(t *testing.T, h *testutil.Harness) func() {
+	t.Helper()
+	name := "test:fail_admin_user"
+	require.NoError(t, h.DB.Callback().Query().Before("gorm:query").Register(name, func(db *gorm.DB) {
+		if db.Statement.Table == model.NewAdminUsers().TableName() {
+			_ = db.AddError(errors.New("database unavailable"))
+		}
+	}))
+	return func() { _ = h.DB.Callback().Query().Remove(name) }
+}
+
+func TestAuthDatabaseError(t *testing.T) {
+	h := testutil.New(t)
+	accessToken := h.Token("admin")
+
+	// 查询失败返回服务器错误，且不缓存为用户不可用
+	restore := failAdminUserQueries(t, h)
+	h.GET("/api/v1/admin/users/info").Token(accessToken).Do().Fail(e.ServerError)
+	restore()
+	h.GET("/api/v1/admin/users/info").Token(accessToken).Do().OK()
+
+	// 用户状态已缓存，超级管理员校验查询失败
+	restore = failAdminUserQueries(t, h)
+	defer restore()
+	h.DELETE("/api/v1/admin/trash/admin_users/").Token(accessToken).JSON(map[string][]uint{"ids": {1}}).Do().Fail(e.ServerError)
+}
+
+func TestLoginRateLimit(t *testing.T) {
+	h := testutil.New(t, testutil.WithConfig(func(cfg *config.Config) { cfg.RateLimit.Enable = true }))
+	login := func(forwardedFor string) *testutil.Response {
+		return h.POST("/api/v1/admin/login/").Header("X-Forwarded-For", forwardedFor).
+			JSON(map[string]string{"username": "admin", "password": "wrong-password"}).Do()
+	}
+
+	// 未配置受信任代理时忽略 X-Forwarded-For，更换伪造的 IP 不能绕过限流
+	for i := 0; i < 10; i++ {
+		login(fmt.Sprintf("203.0.113.%d", i)).Fail(e.FAILURE)
+	}
+	login("203.0.113.100").Status(http.StatusTooManyRequests).Code(e.TooManyRequests)
+}
+
+func TestTrashPurgeBefore(t *testing.T) {
+	h := testutil.New(t)
+	deletedAt := h.Clock.Now().Add(-48 * time.Hour).Unix()
+	// 超过一批的记录需要分批删除
+	rows := make([]*model.Dictionary, 2500)
+	for i := range rows {
+		rows[i] = &model.Dictionary{Type: "purge", Label: fmt.Sprint(i), Value: fmt.Sprint(i)}
+		rows[i].DeletedAt = soft_delete.DeletedAt(deletedAt)
+	}
+	require.NoError(t, h.DB.CreateInBatches(rows, 500).Error)
+
+	purged, err := h.Deps.TrashService().PurgeBefore(h.Context(), h.Clock.Now().Add(-24*time.Hour), "dictionaries")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{"dictionaries": 2500}, purged)
+	h.GET("/api/v1/admin/trash/dictionaries/").As("admin").Do().OK().Field("total", 0)
+}
@@ -2,6 +2,8 @@ package admin
 
 import (
 	"insight/internal/controller"
+	"insight/internal/pkg/openapi"
+	"insight/internal/resources"
 	"insight/internal/service/admin_auth"
 	"insight/internal/validator"
 	"insight/internal/validator/form"
@@ -11,24 +13,60 @@ import (
 
 type AdminUserController struct {
 	controller.Api
+	users *admin_auth.AdminUserService
 }
 
-func NewAdminUserController() *AdminUserController {
-	return &AdminUserController{}
+func NewAdminUserController(users *admin_auth.AdminUserService) *AdminUserController {
+	return &AdminUserController{users: users}
+}
+
+func init() {
+	openapi.Describe((*AdminUserController).GetUserInfo, openapi.Operation{
+		Summary: "当前用户信息", Auth: true, Response: resources.AdminUserResources{},
+		Description: "响应头 ETag 为当前版本号",
+	})
+	openapi.Describe((*AdminUserController).List, openapi.Operation{
+		Summary: "用户列表", Auth: true, Query: form.ListQuery{}, Response: resources.Collection[*resources.AdminUserResources]{},
+	})
+	openapi.Describe((*AdminUserController).Add, openapi.Operation{
+		Summary: "添加用户", Auth: true, Query: form.ID{}, Response: resources.AdminUserResources{},
+	})
+	openapi.Describe((*AdminUserController).Delete, openapi.Operation{
+		Summary: "删除用户", Auth: true, Query: form.ID{}, Response: resources.AdminUserResources{},
+	})
+	openapi.Describe((*AdminUserController).Update, openapi.Operation{
+		Summary: "更新用户资料", Auth: true, Body: form.EditAdminUserForm{},
+		Description: "版本号可通过 If-Match 请求头传入，版本不一致时返回 409，携带 If-Match 时返回 HTTP 412",
+	})
 }
 
 func (api *AdminUserController) GetUserInfo(c *gin.Context) {
-	result, err := admin_auth.NewAdminUserService().GetUserInfo(c.GetUint("uid"))
-	accesstoken := c.GetString("accession")
-	result.SetToken(accesstoken)
+	result, err := api.users.GetUserInfo(c.Request.Context(), c.GetUint("uid"))
 	if err != nil {
 		api.Err(c, err)
 		return
 	}
+	accesstoken := c.GetString("accession")
+	result.SetToken(accesstoken)
+	api.SetETag(c, result.Version)
 	api.Success(c, result)
 	return
 }
 
+// List 用户列表
+func (api *AdminUserController) List(c *gin.Context) {
+	listQuery := form.NewListQuery()
+	if err := validator.CheckQueryParams(c, &listQuery); err != nil {
+		return
+	}
+	res, err := api.users.List(c.Request.Context(), listQuery)
+	if err != nil {
+		api.Err(c, err)
+		return
+	}
+	api.Success(c, res)
+}
+
 func (api *AdminUserController) Add(c *gin.Context) {
 	// 初始化参数结构
 	IDForm := form.NewIDForm()
@@ -38,7 +76,7 @@ func (api *AdminUserController) Add(c *gin.Context) {
 		return
 	}
 
-	result, err := admin_auth.NewAdminUserService().GetUserInfo(IDForm.ID)
+	result, err := api.users.GetUserInfo(c.Request.Context(), IDForm.ID)
 	if err != nil {
 		api.Err(c, err)
 		return
@@ -56,7 +94,7 @@ func (api *AdminUserController) Delete(c *gin.Context) {
 		return
 	}
 
-	result, err := admin_auth.NewAdminUserService().GetUserInfo(IDForm.ID)
+	result, err := api.users.GetUserInfo(c.Request.Context(), IDForm.ID)
 	if err != nil {
 		api.Err(c, err)
 		return
@@ -64,3 +102,24 @@ func (api *AdminUserController) Delete(c *gin.Context) {
 	api.Success(c, result)
 	return
 }
+
+// Update 更新用户资料，版本号可通过参数或 If-Match 请求头传入
+func (api *AdminUserController) Update(c *gin.Context) {
+	editForm := form.NewEditAdminUserForm()
+	version, ifMatch := api.IfMatch(c)
+	editForm.Version = version
+
+	if err := validator.CheckPostParams(c, &editForm); err != nil {
+		return
+	}
+	if ifMatch {
+		editForm.Version = version
+	}
+
+	if err := api.users.Update(c.Request.Context(), editForm); err != nil {
+		api.Err(c, err)
+		return
+	}
+	api.SetETag(c, editForm.Version+1)
+	api.Success(c)
+}
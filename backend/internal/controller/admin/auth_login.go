@@ -2,6 +2,7 @@ package admin
 
 import (
 	"insight/internal/controller"
+	"insight/internal/pkg/openapi"
 	"insight/internal/service/admin_auth"
 	"insight/internal/validator"
 	"insight/internal/validator/form"
@@ -11,10 +12,17 @@ import (
 
 type LoginController struct {
 	controller.Api
+	login *admin_auth.LoginService
 }
 
-func NewLoginController() *LoginController {
-	return &LoginController{}
+func NewLoginController(login *admin_auth.LoginService) *LoginController {
+	return &LoginController{login: login}
+}
+
+func init() {
+	openapi.Describe((*LoginController).Login, openapi.Operation{
+		Summary: "管理员登录", Body: form.LoginAuth{}, Response: admin_auth.TokenResponse{},
+	})
 }
 
 // Login 管理员登录
@@ -26,7 +34,7 @@ func (api *LoginController) Login(c *gin.Context) {
 		return
 	}
 
-	result, err := admin_auth.NewLoginService().Login(loginForm.UserName, loginForm.PassWord)
+	result, err := api.login.Login(c.Request.Context(), loginForm.UserName, loginForm.PassWord)
 	if err != nil {
 		api.Err(c, err)
 		return
@@ -2,6 +2,9 @@ package admin
 
 import (
 	"insight/internal/controller"
+	e "insight/internal/pkg/errors"
+	"insight/internal/pkg/openapi"
+	"insight/internal/resources"
 	"insight/internal/service/admin_auth"
 	"insight/internal/validator"
 	"insight/internal/validator/form"
@@ -11,29 +14,69 @@ import (
 
 type PermissionController struct {
 	controller.Api
+	permissions *admin_auth.PermissionService
 }
 
-func NewPermissionController() *PermissionController {
-	return &PermissionController{}
+func NewPermissionController(permissions *admin_auth.PermissionService) *PermissionController {
+	return &PermissionController{permissions: permissions}
+}
+
+func init() {
+	openapi.Describe((*PermissionController).Edit, openapi.Operation{
+		Summary: "添加或更新权限", Auth: true, Body: form.EditPermission{},
+		Description: "id 为空时添加，否则按版本号更新，版本号可通过 If-Match 请求头传入",
+	})
+	openapi.Describe((*PermissionController).Detail, openapi.Operation{
+		Summary: "权限详情", Auth: true, Path: form.ID{}, Response: resources.PermissionResources{},
+		Description: "响应头 ETag 为当前版本号",
+	})
+	openapi.Describe(PermissionController.List, openapi.Operation{
+		Summary: "权限列表", Auth: true, Query: form.ListPermission{}, Response: resources.Collection[*resources.PermissionResources]{},
+	})
 }
 
 func (api *PermissionController) Edit(c *gin.Context) {
 	// 初始化参数结构体
 	permissionForm := form.NewEditPermissionForm()
+	// If-Match 中的版本号优先于参数中的版本号
+	version, ifMatch := api.IfMatch(c)
+	permissionForm.Version = version
 
 	// 绑定参数并使用验证器验证参数
 	if err := validator.CheckPostParams(c, &permissionForm); err != nil {
 		return
 	}
+	if ifMatch {
+		permissionForm.Version = version
+	}
 
-	err := admin_auth.NewPermissionService().Edit(permissionForm)
+	err := api.permissions.Edit(c.Request.Context(), permissionForm)
 	if err != nil {
 		api.Err(c, err)
 		return
 	}
+	if permissionForm.Id > 0 {
+		api.SetETag(c, permissionForm.Version+1)
+	}
 	api.Success(c, nil)
 }
 
+// Detail 权限详情，响应头 ETag 为当前版本号
+func (api *PermissionController) Detail(c *gin.Context) {
+	IDForm := form.NewIDForm()
+	if err := c.ShouldBindUri(IDForm); err != nil {
+		api.FailCode(c, e.InvalidParameter)
+		return
+	}
+	res, err := api.permissions.Detail(c.Request.Context(), IDForm.ID)
+	if err != nil {
+		api.Err(c, err)
+		return
+	}
+	api.SetETag(c, res.Version)
+	api.Success(c, res)
+}
+
 func (api PermissionController) List(c *gin.Context) {
 	// 初始化参数结构体
 	permissionQuery := form.NewListPermissionQuery()
@@ -41,6 +84,10 @@ func (api PermissionController) List(c *gin.Context) {
 	if err := validator.CheckQueryParams(c, &permissionQuery); err != nil {
 		return
 	}
-	res := admin_auth.NewPermissionService().ListPage(permissionQuery)
+	res, err := api.permissions.ListPage(c.Request.Context(), permissionQuery)
+	if err != nil {
+		api.Err(c, err)
+		return
+	}
 	api.Success(c, res)
 }
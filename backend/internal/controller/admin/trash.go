@@ -0,0 +1,81 @@
+package admin
+
+import (
+	"insight/internal/controller"
+	"insight/internal/model"
+	"insight/internal/pkg/openapi"
+	"insight/internal/resources"
+	"insight/internal/service/admin_auth"
+	"insight/internal/validator"
+	"insight/internal/validator/form"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TrashController struct {
+	controller.Api
+	trash *admin_auth.TrashService
+}
+
+func NewTrashController(trash *admin_auth.TrashService) *TrashController {
+	return &TrashController{trash: trash}
+}
+
+func init() {
+	description := "resource 可选 " + strings.Join(model.TrashResources(), "、")
+	openapi.Describe((*TrashController).List, openapi.Operation{
+		Summary: "回收站列表", Description: description, Auth: true,
+		Query: form.ListQuery{}, Response: resources.Collection[*resources.TrashResources]{},
+	})
+	openapi.Describe((*TrashController).Restore, openapi.Operation{
+		Summary: "恢复记录", Description: description, Auth: true, Body: form.IDs{},
+	})
+	openapi.Describe((*TrashController).Purge, openapi.Operation{
+		Summary: "永久删除记录", Description: description + "；仅超级管理员可以操作", Auth: true, Body: form.IDs{},
+		Response: struct {
+			Purged int64 `json:"purged"`
+		}{},
+	})
+}
+
+// List 回收站列表
+func (api *TrashController) List(c *gin.Context) {
+	listQuery := form.NewListQuery()
+	if err := validator.CheckQueryParams(c, &listQuery); err != nil {
+		return
+	}
+	res, err := api.trash.List(c.Request.Context(), c.Param("resource"), listQuery)
+	if err != nil {
+		api.Err(c, err)
+		return
+	}
+	api.Success(c, res)
+}
+
+// Restore 恢复记录
+func (api *TrashController) Restore(c *gin.Context) {
+	idsForm := form.NewIDsForm()
+	if err := validator.CheckPostParams(c, &idsForm); err != nil {
+		return
+	}
+	if err := api.trash.Restore(c.Request.Context(), c.Param("resource"), idsForm.IDs); err != nil {
+		api.Err(c, err)
+		return
+	}
+	api.Success(c)
+}
+
+// Purge 永久删除记录
+func (api *TrashController) Purge(c *gin.Context) {
+	idsForm := form.NewIDsForm()
+	if err := validator.CheckPostParams(c, &idsForm); err != nil {
+		return
+	}
+	purged, err := api.trash.Purge(c.Request.Context(), c.Param("resource"), idsForm.IDs)
+	if err != nil {
+		api.Err(c, err)
+		return
+	}
+	api.Success(c, gin.H{"purged": purged})
+}
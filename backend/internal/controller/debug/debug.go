@@ -0,0 +1,112 @@
+package debug
+
+import (
+	"insight/data"
+	"insight/internal/controller"
+	"insight/internal/pkg/diagnostics"
+	e "insight/internal/pkg/errors"
+	"insight/internal/pkg/openapi"
+	"net/http"
+	"net/http/pprof"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// DebugController pprof 与运行时诊断
+type DebugController struct {
+	controller.Api
+	db *gorm.DB
+}
+
+// NewDebugController db 为 nil 表示未开启数据库
+func NewDebugController(db *gorm.DB) *DebugController {
+	return &DebugController{db: db}
+}
+
+func init() {
+	openapi.Describe(DebugController.PprofIndex, openapi.Operation{Summary: "pprof 索引", Raw: true, Auth: true})
+	openapi.Describe(DebugController.Pprof, openapi.Operation{
+		Summary: "pprof 采样", Raw: true, Auth: true,
+		Description: "name 为 heap、goroutine、allocs、block、mutex、threadcreate、profile、trace、cmdline 或 symbol，参数与 net/http/pprof 相同；" +
+			"profile、trace 的 seconds 超出 system.write_timeout 时按写超时减一秒采样",
+	})
+	openapi.Describe(DebugController.Goroutines, openapi.Operation{Summary: "协程调用栈", Raw: true, Auth: true})
+	openapi.Describe(DebugController.Runtime, openapi.Operation{
+		Summary: "运行时与 GC 状态", Auth: true, Response: diagnostics.RuntimeStats{},
+	})
+	openapi.Describe(DebugController.Database, openapi.Operation{
+		Summary: "数据库连接池状态", Auth: true, Response: []diagnostics.DBStats{},
+	})
+}
+
+// PprofIndex 列出全部 profile
+func (api DebugController) PprofIndex(c *gin.Context) {
+	pprof.Index(c.Writer, c.Request)
+}
+
+// Pprof 按名称采样，路径前缀与 net/http/pprof 不同，需按名称分发
+func (api DebugController) Pprof(c *gin.Context) {
+	var handler http.Handler
+	switch name := c.Param("name"); name {
+	case "cmdline":
+		handler = http.HandlerFunc(pprof.Cmdline)
+	case "profile":
+		limitSeconds(c.Request, 30)
+		handler = http.HandlerFunc(pprof.Profile)
+	case "symbol":
+		handler = http.HandlerFunc(pprof.Symbol)
+	case "trace":
+		limitSeconds(c.Request, 1)
+		handler = http.HandlerFunc(pprof.Trace)
+	default:
+		handler = pprof.Handler(name)
+	}
+	handler.ServeHTTP(c.Writer, c.Request)
+}
+
+// limitSeconds 采样时长不小于服务的写超时时 net/http/pprof 会拒绝采样，
+// 此时改为按写超时减一秒采样，defaultSeconds 为 net/http/pprof 的默认时长
+func limitSeconds(r *http.Request, defaultSeconds int) {
+	srv, ok := r.Context().Value(http.ServerContextKey).(*http.Server)
+	if !ok || srv.WriteTimeout <= time.Second {
+		return
+	}
+	query := r.URL.Query()
+	seconds, err := strconv.Atoi(query.Get("seconds"))
+	if err != nil || seconds <= 0 {
+		seconds = defaultSeconds
+	}
+	if limit := int((srv.WriteTimeout - time.Second) / time.Second); seconds > limit {
+		query.Set("seconds", strconv.Itoa(limit))
+		r.URL.RawQuery = query.Encode()
+	}
+}
+
+// Goroutines 以文本形式返回全部协程的调用栈
+func (api DebugController) Goroutines(c *gin.Context) {
+	c.Header("Content-Type", "text/plain; charset=utf-8")
+	c.Status(http.StatusOK)
+	_ = diagnostics.Goroutines(c.Writer)
+}
+
+// Runtime 内存、GC 与协程数量
+func (api DebugController) Runtime(c *gin.Context) {
+	api.Success(c, diagnostics.Runtime())
+}
+
+// Database 主库与从库的连接池状态
+func (api DebugController) Database(c *gin.Context) {
+	if api.db == nil {
+		api.Err(c, e.NewBusinessError(e.FAILURE, "数据库未开启"))
+		return
+	}
+	pools, err := data.Stats(api.db)
+	if err != nil {
+		api.Err(c, err)
+		return
+	}
+	api.Success(c, diagnostics.Database(pools))
+}
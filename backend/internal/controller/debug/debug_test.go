@@ -0,0 +1,85 @@
+package debug_test
+
+import (
+	"insight/config"
+	e "insight/internal/pkg/errors"
+	"insight/internal/routers"
+	"insight/internal/testutil"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDebugAuth(t *testing.T) {
+	h := testutil.New(t)
+
+	h.GET("/admin/debug/runtime").Do().Fail(e.NotLogin)
+	h.GET("/admin/debug/runtime").As("viewer").Do().Fail(e.AuthorizationError)
+	h.GET("/admin/debug/pprof/heap").As("viewer").Do().Fail(e.AuthorizationError)
+	// 没有角色的用户
+	h.GET("/admin/debug/runtime").Token(h.TokenWithRoles()).Do().Fail(e.AuthorizationError)
+	h.GET("/admin/debug/runtime").Token(h.TokenWithRoles("super_admin")).Do().OK()
+}
+
+func TestDebug(t *testing.T) {
+	h := testutil.New(t)
+
+	h.GET("/admin/debug/runtime").As("admin").Do().OK().Field("num_cpu", runtime.NumCPU())
+	h.GET("/admin/debug/database").As("admin").Do().OK().Field("0.name", "primary")
+
+	goroutines := h.GET("/admin/debug/goroutines").As("admin").Do().Status(http.StatusOK)
+	assert.Contains(t, goroutines.Recorder.Body.String(), "goroutine ")
+
+	index := h.GET("/admin/debug/pprof/").As("admin").Do().Status(http.StatusOK)
+	assert.Contains(t, index.Recorder.Body.String(), "heap")
+	heap := h.GET("/admin/debug/pprof/heap").As("admin").Do().Status(http.StatusOK)
+	assert.NotEmpty(t, heap.Recorder.Body.Bytes())
+	h.GET("/admin/debug/pprof/heap").As("admin").Query("debug", "1").Do().Status(http.StatusOK)
+	h.GET("/admin/debug/pprof/unknown").As("admin").Do().Status(http.StatusNotFound)
+}
+
+func TestDebugAddr(t *testing.T) {
+	h := testutil.New(t, testutil.WithConfig(func(cfg *config.Config) { cfg.Debug.Addr = "127.0.0.1:6060" }))
+	h.GET("/admin/debug/runtime").As("admin").Do().Status(http.StatusNotFound)
+
+	// 单独监听的诊断接口不做鉴权
+	engine := routers.NewDebugEngine(h.Deps)
+	recorder := httptest.NewRecorder()
+	engine.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/debug/runtime", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `"go_version"`)
+	recorder = httptest.NewRecorder()
+	engine.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/debug/pprof/goroutine", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	h = testutil.New(t, testutil.WithConfig(func(cfg *config.Config) { cfg.Debug.Enable = false }))
+	h.GET("/admin/debug/runtime").As("admin").Do().Status(http.StatusNotFound)
+}
+
+func TestPprofWriteTimeout(t *testing.T) {
+	h := testutil.New(t)
+	server := httptest.NewUnstartedServer(h.Engine)
+	server.Config.WriteTimeout = 2 * time.Second
+	server.Start()
+	defer server.Close()
+
+	// 采样时长超出写超时时按写超时减一秒采样，而不是被 net/http/pprof 拒绝
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/admin/debug/pprof/trace?seconds=5", nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+h.Token("admin"))
+	start := time.Now()
+	resp, err := server.Client().Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/octet-stream", resp.Header.Get("Content-Type"), string(body))
+	assert.Less(t, time.Since(start), 2*time.Second)
+}
@@ -2,7 +2,10 @@ package demo
 
 import (
 	"insight/internal/controller"
+	"insight/internal/pkg/agent"
+	"insight/internal/pkg/errors"
 	log "insight/internal/pkg/logger"
+	"insight/internal/pkg/openapi"
 	"insight/internal/service"
 	"time"
 
@@ -12,45 +15,65 @@ import (
 
 type DemoController struct {
 	controller.Api
+	demo service.DemoService
 }
 
-func NewDemoController() *DemoController {
-	return &DemoController{}
+func NewDemoController(demo service.DemoService) *DemoController {
+	return &DemoController{demo: demo}
+}
+
+func init() {
+	openapi.Describe(DemoController.Demo, openapi.Operation{
+		Summary: "示例接口", Response: "",
+		Query: struct {
+			Path string `form:"path"`
+		}{},
+	})
+	openapi.Describe(DemoController.Agent, openapi.Operation{
+		Summary: "调用 agent 的 Greeter 服务", Response: struct {
+			Message string `json:"message"`
+		}{},
+		Query: struct {
+			Name string `form:"name" label:"名称，默认 insight"`
+		}{},
+	})
 }
 
 func (api DemoController) Demo(c *gin.Context) {
 	start := time.Now()
+	logger := log.WithContext(c.Request.Context())
 
-	log.Logger.Info("Processing Demo request",
-		zap.String("method", c.Request.Method),
-		zap.String("path", c.Request.URL.Path),
-		zap.String("client_ip", c.ClientIP()),
-	)
+	logger.Info("Processing Demo request", zap.String("client_ip", c.ClientIP()))
 
 	path := c.Query("path")
 
-	log.Logger.Info("Received path parameter",
+	logger.Info("Received path parameter",
 		zap.String("path", path),
 	)
 
-	result, err := service.NewDemoService().Demo(path)
+	result, err := api.demo.Demo(c.Request.Context(), path)
 	if err != nil {
-		log.Logger.Error("Demo service call failed",
-			zap.Error(err),
-			zap.String("method", c.Request.Method),
-			zap.String("path", c.Request.URL.Path),
-		)
+		logger.Error("Demo service call failed", zap.Error(err))
 		api.Err(c, err)
 		return
 	}
 
 	duration := time.Since(start)
-	log.Logger.Info("Demo request processed successfully",
-		zap.String("method", c.Request.Method),
-		zap.String("path", c.Request.URL.Path),
+	logger.Info("Demo request processed successfully",
 		zap.Duration("duration", duration),
 		zap.Any("result", result),
 	)
 
 	api.Success(c, result)
 }
+
+// Agent 调用 Python agent 的 gRPC Greeter 服务
+func (api DemoController) Agent(c *gin.Context) {
+	reply, err := agent.SayHello(c.Request.Context(), c.DefaultQuery("name", "insight"), 0)
+	if err != nil {
+		log.WithContext(c.Request.Context()).Error("Agent call failed", zap.Error(err))
+		api.FailCode(c, errors.ServerError)
+		return
+	}
+	api.Success(c, gin.H{"message": reply.Message})
+}
@@ -0,0 +1,56 @@
+package docs
+
+import (
+	"encoding/json"
+	"insight/internal/controller"
+	e "insight/internal/pkg/errors"
+	log "insight/internal/pkg/logger"
+	"insight/internal/pkg/openapi"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// ui Swagger UI 页面与 openapi.json 挂载在同一路由组下
+var ui = openapi.UIHandler("Insight API", "../openapi.json")
+
+type DocsController struct {
+	controller.Api
+	spec func() ([]byte, error)
+}
+
+func NewDocsController() *DocsController {
+	return &DocsController{}
+}
+
+func init() {
+	openapi.Describe(DocsController.Spec, openapi.Operation{Hidden: true})
+	openapi.Describe(DocsController.UI, openapi.Operation{Hidden: true})
+}
+
+// SetSpec 设置文档的生成函数，文档在第一次请求时生成
+func (api *DocsController) SetSpec(build func() *openapi.Document) {
+	api.spec = sync.OnceValues(func() ([]byte, error) {
+		return json.Marshal(build())
+	})
+}
+
+// Spec OpenAPI 文档
+func (api DocsController) Spec(c *gin.Context) {
+	spec, err := api.spec()
+	if err != nil {
+		log.WithContext(c.Request.Context()).Error("Failed to generate OpenAPI document", zap.Error(err))
+		api.FailCode(c, e.ServerError)
+		return
+	}
+	c.Data(http.StatusOK, "application/json; charset=utf-8", spec)
+}
+
+// UI Swagger UI 页面与静态文件
+func (api DocsController) UI(c *gin.Context) {
+	prefix := strings.TrimSuffix(c.FullPath(), "/*filepath")
+	http.StripPrefix(prefix, ui).ServeHTTP(c.Writer, c.Request)
+}
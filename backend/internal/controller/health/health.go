@@ -0,0 +1,52 @@
+package health
+
+import (
+	"insight/internal/controller"
+	"insight/internal/pkg/health"
+	"insight/internal/pkg/openapi"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type HealthController struct {
+	controller.Api
+}
+
+func NewHealthController() *HealthController {
+	return &HealthController{}
+}
+
+func init() {
+	openapi.Describe(HealthController.Healthz, openapi.Operation{
+		Summary: "存活探针", Raw: true, Response: struct {
+			Status string `json:"status"`
+		}{},
+	})
+	openapi.Describe(HealthController.Readyz, openapi.Operation{
+		Summary: "就绪探针", Description: "关键依赖不可用时返回 HTTP 503", Raw: true, Response: health.Report{},
+	})
+	openapi.Describe(HealthController.Status, openapi.Operation{
+		Summary: "依赖状态", Auth: true, Response: health.Report{},
+	})
+}
+
+// Healthz 存活探针，进程能处理请求即返回 200，不检查外部依赖
+func (api HealthController) Healthz(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": health.StatusUp})
+}
+
+// Readyz 就绪探针，关键依赖不可用时返回 503，探针依赖 HTTP 状态码而不是业务码
+func (api HealthController) Readyz(c *gin.Context) {
+	report := health.Default().Run(c.Request.Context(), true)
+	code := http.StatusOK
+	if !report.Healthy() {
+		code = http.StatusServiceUnavailable
+	}
+	c.JSON(code, report)
+}
+
+// Status 全部检查项的详细状态，包含耗时与最近一次错误
+func (api HealthController) Status(c *gin.Context) {
+	api.Success(c, health.Default().Run(c.Request.Context(), false))
+}
@@ -3,6 +3,7 @@ package hello
 import (
 	"insight/internal/controller"
 	log "insight/internal/pkg/logger"
+	"insight/internal/pkg/openapi"
 	"insight/internal/service"
 	"time"
 
@@ -12,35 +13,31 @@ import (
 
 type HelloController struct {
 	controller.Api
+	hello service.HelloService
 }
 
-func NewHelloController() *HelloController {
-	return &HelloController{}
+func NewHelloController(hello service.HelloService) *HelloController {
+	return &HelloController{hello: hello}
+}
+
+func init() {
+	openapi.Describe(HelloController.HelloGin, openapi.Operation{Summary: "Hello", Response: ""})
 }
 
 func (api HelloController) HelloGin(c *gin.Context) {
 	start := time.Now()
-	log.Logger.Info("Processing Hello request",
-		zap.String("method", c.Request.Method),
-		zap.String("path", c.Request.URL.Path),
-		zap.String("client_ip", c.ClientIP()),
-	)
+	logger := log.WithContext(c.Request.Context())
+	logger.Info("Processing Hello request", zap.String("client_ip", c.ClientIP()))
 
-	result, err := service.NewHelloService().Hello()
+	result, err := api.hello.Hello()
 	if err != nil {
-		log.Logger.Error("Hello service call failed",
-			zap.Error(err),
-			zap.String("method", c.Request.Method),
-			zap.String("path", c.Request.URL.Path),
-		)
+		logger.Error("Hello service call failed", zap.Error(err))
 		api.Err(c, err)
 		return
 	}
 
 	duration := time.Since(start)
-	log.Logger.Info("Hello request processed successfully",
-		zap.String("method", c.Request.Method),
-		zap.String("path", c.Request.URL.Path),
+	logger.Info("Hello request processed successfully",
 		zap.Duration("duration", duration),
 		zap.Any("result", result),
 	)
@@ -4,6 +4,8 @@ import (
 	"insight/internal/pkg/errors"
 	r "insight/internal/pkg/response"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
@@ -49,5 +51,28 @@ func (api *Api) Err(c *gin.Context, e error) {
 		return
 	}
 
-	api.Fail(c, businessError.GetCode(), businessError.GetMessage())
+	response := r.Resp()
+	// 携带 If-Match 的请求版本不一致时按 HTTP 语义返回 412
+	if businessError.GetCode() == errors.Conflict && c.GetHeader("If-Match") != "" {
+		response.SetHttpCode(http.StatusPreconditionFailed)
+	}
+	if data := businessError.GetData(); data != nil {
+		response.WithData(data)
+	}
+	response.FailCode(c, businessError.GetCode(), businessError.GetMessage())
+}
+
+// SetETag 以资源版本号设置 ETag 响应头
+func (api *Api) SetETag(c *gin.Context, version uint) {
+	c.Header("ETag", strconv.Quote(strconv.FormatUint(uint64(version), 10)))
+}
+
+// IfMatch 解析 If-Match 请求头中的版本号
+func (api *Api) IfMatch(c *gin.Context) (uint, bool) {
+	tag := strings.TrimPrefix(c.GetHeader("If-Match"), "W/")
+	version, err := strconv.ParseUint(strings.Trim(tag, `"`), 10, 64)
+	if err != nil || version == 0 {
+		return 0, false
+	}
+	return uint(version), true
 }
@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"insight/config"
+	log "insight/internal/pkg/logger"
+	"insight/internal/pkg/response"
+	"math/rand/v2"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// AccessLog 记录请求开始时间并在请求结束后输出结构化访问日志，
+// request_id、user_id、route 等字段来自请求级日志对象
+func AccessLog() gin.HandlerFunc {
+	cfg := config.GetConfig().Logger.Access
+	skip := make(map[string]struct{}, len(cfg.SkipPaths))
+	for _, path := range cfg.SkipPaths {
+		skip[path] = struct{}{}
+	}
+
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Set(response.StartTimeKey, start)
+		c.Next()
+
+		if !cfg.Enable {
+			return
+		}
+		if _, ok := skip[c.Request.URL.Path]; ok {
+			return
+		}
+		status := c.Writer.Status()
+		level := zapcore.InfoLevel
+		switch {
+		case status >= http.StatusInternalServerError:
+			level = zapcore.ErrorLevel
+		case status >= http.StatusBadRequest:
+			level = zapcore.WarnLevel
+		case cfg.SampleRate < 1 && rand.Float64() >= cfg.SampleRate:
+			return
+		}
+
+		fields := []zap.Field{
+			zap.String("path", c.Request.URL.Path),
+			zap.Int("status", status),
+			zap.Duration("latency", time.Since(start)),
+			zap.Int("bytes", max(c.Writer.Size(), 0)),
+			zap.String("client_ip", c.ClientIP()),
+		}
+		if code, ok := c.Get(response.CodeKey); ok {
+			fields = append(fields, zap.Int("code", code.(int)))
+		}
+		if len(c.Errors) > 0 {
+			fields = append(fields, zap.String("errors", c.Errors.ByType(gin.ErrorTypePrivate).String()))
+		}
+		log.WithContext(c.Request.Context()).Log(level, "HTTP request", fields...)
+	}
+}
@@ -3,7 +3,9 @@ package middleware
 import (
 	cfg "insight/config"
 	"insight/internal/global"
+	"insight/internal/pkg/clock"
 	e "insight/internal/pkg/errors"
+	log "insight/internal/pkg/logger"
 	"insight/internal/pkg/response"
 	"insight/internal/pkg/utils/token"
 	"insight/internal/service/admin_auth"
@@ -12,9 +14,11 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/zap"
 )
 
-func AdminAuthHandler() gin.HandlerFunc {
+// AdminAuthHandler 后台登录鉴权，users 校验用户是否可用，login 在令牌即将过期时刷新令牌，令牌是否过期按 clk 判断
+func AdminAuthHandler(users *admin_auth.AdminUserService, login *admin_auth.LoginService, clk clock.Clock) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authorization := c.GetHeader("Authorization")
 		accessToken, err := token.GetAccessToken(authorization)
@@ -24,7 +28,7 @@ func AdminAuthHandler() gin.HandlerFunc {
 		}
 		adminCustomClaims := new(token.AdminCustomClaims)
 		// 解析token
-		err = token.Parse(accessToken, adminCustomClaims, jwt.WithSubject(global.Subject))
+		err = token.Parse(accessToken, adminCustomClaims, jwt.WithSubject(global.Subject), jwt.WithTimeFunc(clk.Now))
 		if err != nil || adminCustomClaims == nil {
 			response.FailCode(c, e.NotLogin)
 			return
@@ -37,25 +41,37 @@ func AdminAuthHandler() gin.HandlerFunc {
 			return
 		}
 
+		// 用户被删除或禁用后令牌立即失效
+		active, err := users.IsActive(c.Request.Context(), adminCustomClaims.UserID)
+		if err != nil {
+			log.WithContext(c.Request.Context()).Error("Failed to check admin user status", zap.Uint("user_id", adminCustomClaims.UserID), zap.Error(err))
+			response.FailCode(c, e.ServerError)
+			return
+		}
+		if !active {
+			response.FailCode(c, e.NotLogin)
+			return
+		}
+		c.Request = c.Request.WithContext(log.WithFields(c.Request.Context(), zap.Uint("user_id", adminCustomClaims.UserID)))
+		// 未开启令牌刷新时同样需要设置，限流等中间件按 uid 识别用户
+		c.Set("uid", adminCustomClaims.UserID)
+		c.Set("mobile", adminCustomClaims.Mobile)
+		c.Set("user", adminCustomClaims.Nickname)
+		c.Set("email", adminCustomClaims.Email)
+		c.Set("accession", accessToken)
+
 		// 刷新时间大于0则判断剩余时间小于刷新时间
 		if cfg.GetConfig().Jwt.RefreshTTL > 0 {
-			now := time.Now()
+			now := clk.Now()
 			diff := exp.Time.Sub(now)
 			refreshTTL := cfg.GetConfig().Jwt.RefreshTTL * time.Second
 			if diff < refreshTTL {
-				tokenResponse, _ := admin_auth.NewLoginService().Refresh(adminCustomClaims.UserID)
+				tokenResponse, _ := login.Refresh(c.Request.Context(), adminCustomClaims.UserID)
 				c.Writer.Header().Set("refresh-access-token", tokenResponse.AccessToken)
 				c.Writer.Header().Set("refresh-exp", strconv.FormatInt(tokenResponse.ExpiresAt, 10))
 
 			}
-
-			c.Set("uid", adminCustomClaims.UserID)
-			c.Set("mobile", adminCustomClaims.Mobile)
-			c.Set("user", adminCustomClaims.Nickname)
-			c.Set("email", adminCustomClaims.Email)
-			c.Set("accession", accessToken)
-			c.Next()
-
 		}
+		c.Next()
 	}
 }
@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"insight/config"
+	"insight/internal/pkg/certs"
+	e "insight/internal/pkg/errors"
+	log "insight/internal/pkg/logger"
+	"insight/internal/pkg/response"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// ClientIdentityKey 上下文中客户端证书对应的身份
+const ClientIdentityKey = "client_identity"
+
+// ClientCert 将已校验的客户端证书映射为身份，保存到上下文并加入请求日志。
+// 配置了 tls.client_identities 时拒绝未列出的证书，未提供证书的请求不受影响
+func ClientCert() gin.HandlerFunc {
+	identities := config.GetConfig().TLS.ClientIdentities
+	return func(c *gin.Context) {
+		if c.Request.TLS == nil || len(c.Request.TLS.VerifiedChains) == 0 {
+			c.Next()
+			return
+		}
+		identity, ok := certs.Identity(c.Request.TLS, identities)
+		if !ok {
+			response.FailCode(c, e.AuthorizationError)
+			return
+		}
+		c.Set(ClientIdentityKey, identity)
+		c.Request = c.Request.WithContext(log.WithFields(c.Request.Context(), zap.String("client", identity)))
+		c.Next()
+	}
+}
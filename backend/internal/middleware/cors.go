@@ -1,10 +1,83 @@
 package middleware
 
 import (
+	"errors"
+	"insight/config"
+	"insight/config/autoload"
+	log "insight/internal/pkg/logger"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
 )
 
+// devOrigins 开发与测试环境未配置 allow_origins 时允许本机前端访问
+var devOrigins = []string{"http://localhost:*", "http://127.0.0.1:*"}
+
+var corsOrigins atomic.Pointer[[]string]
+
+// CorsHandler 按 cors 配置处理跨域请求，允许的来源在配置文件修改后即时生效，其余选项需重启
 func CorsHandler() gin.HandlerFunc {
-	return cors.Default()
+	cfg := config.GetConfig()
+	setCorsOrigins(cfg)
+	config.OnChange(func(changed *config.Config) {
+		// allow_credentials 需重启生效，按启动时的取值校验新的来源
+		corsConfig := changed.Cors
+		corsConfig.AllowCredentials = cfg.Cors.AllowCredentials
+		if err := ValidateCors(corsConfig); err != nil {
+			log.Logger.Error("CORS origins not reloaded", zap.Error(err))
+			return
+		}
+		setCorsOrigins(changed)
+		log.Logger.Info("CORS origins reloaded", zap.Strings("origins", *corsOrigins.Load()))
+	})
+
+	return cors.New(cors.Config{
+		AllowOriginFunc:  allowOrigin,
+		AllowMethods:     cfg.Cors.AllowMethods,
+		AllowHeaders:     cfg.Cors.AllowHeaders,
+		ExposeHeaders:    cfg.Cors.ExposeHeaders,
+		AllowCredentials: cfg.Cors.AllowCredentials,
+		MaxAge:           time.Duration(cfg.Cors.MaxAge) * time.Second,
+	})
+}
+
+// ValidateCors 携带凭证时不允许单独的 * 来源，否则任意站点都可以用户身份发起跨域请求
+func ValidateCors(cfg autoload.CorsConfig) error {
+	if cfg.AllowCredentials && slices.Contains(cfg.AllowOrigins, "*") {
+		return errors.New(`cors: allow_origins must not contain "*" when allow_credentials is true`)
+	}
+	return nil
+}
+
+func setCorsOrigins(cfg *config.Config) {
+	origins := cfg.Cors.AllowOrigins
+	if len(origins) == 0 && (cfg.System.Env == "dev" || cfg.System.Env == "test") {
+		origins = devOrigins
+	}
+	corsOrigins.Store(&origins)
+}
+
+func allowOrigin(origin string) bool {
+	for _, pattern := range *corsOrigins.Load() {
+		if matchOrigin(pattern, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchOrigin 比较来源，pattern 中可包含一个 * 匹配任意字符，单独的 * 匹配所有来源
+func matchOrigin(pattern, origin string) bool {
+	prefix, suffix, wildcard := strings.Cut(pattern, "*")
+	if !wildcard {
+		return strings.EqualFold(pattern, origin)
+	}
+	return len(origin) >= len(prefix)+len(suffix) &&
+		strings.EqualFold(origin[:len(prefix)], prefix) &&
+		strings.EqualFold(origin[len(origin)-len(suffix):], suffix)
 }
@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"insight/config"
+	"insight/config/autoload"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchOrigin(t *testing.T) {
+	tests := []struct {
+		pattern, origin string
+		want            bool
+	}{
+		{"https://app.example.com", "https://app.example.com", true},
+		{"https://app.example.com", "HTTPS://App.Example.COM", true},
+		{"https://app.example.com", "http://app.example.com", false},
+		{"https://*.example.com", "https://a.example.com", true},
+		{"https://*.example.com", "https://a.b.example.com", true},
+		{"https://*.Example.com", "https://A.example.COM", true},
+		{"https://*.example.com", "https://example.com", false},
+		{"https://*.example.com", "https://a.example.com.evil.com", false},
+		{"https://*.example.com", "https://a.example.com:8443", false},
+		{"https://*.example.com", "http://a.example.com", false},
+		{"http://localhost:*", "http://localhost:3000", true},
+		{"http://localhost:*", "http://localhost", false},
+		{"http://localhost:3000", "http://localhost:3001", false},
+		{"*", "https://any.example.org", true},
+		{"*", "null", true},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, matchOrigin(tt.pattern, tt.origin), "%s %s", tt.pattern, tt.origin)
+	}
+}
+
+func TestSetCorsOrigins(t *testing.T) {
+	tests := []struct {
+		env     string
+		origins []string
+		origin  string
+		want    bool
+	}{
+		{"dev", nil, "http://localhost:5173", true},
+		{"test", nil, "http://127.0.0.1:8080", true},
+		{"dev", nil, "https://app.example.com", false},
+		// 配置了来源时不再追加本机来源
+		{"dev", []string{"https://app.example.com"}, "http://localhost:5173", false},
+		{"production", nil, "http://localhost:5173", false},
+		{"production", []string{}, "https://app.example.com", false},
+		{"production", []string{"https://*.example.com"}, "https://app.example.com", true},
+	}
+	for _, tt := range tests {
+		cfg := config.Defaults()
+		cfg.System.Env = tt.env
+		cfg.Cors.AllowOrigins = tt.origins
+		setCorsOrigins(cfg)
+		assert.Equal(t, tt.want, allowOrigin(tt.origin), "%s %v %s", tt.env, tt.origins, tt.origin)
+	}
+}
+
+func TestValidateCors(t *testing.T) {
+	assert.NoError(t, ValidateCors(autoload.CorsConfig{AllowOrigins: []string{"*"}}))
+	assert.NoError(t, ValidateCors(autoload.CorsConfig{AllowOrigins: []string{"https://*.example.com"}, AllowCredentials: true}))
+	assert.Error(t, ValidateCors(autoload.CorsConfig{AllowOrigins: []string{"https://app.example.com", "*"}, AllowCredentials: true}))
+}
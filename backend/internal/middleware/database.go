@@ -0,0 +1,18 @@
+package middleware
+
+import (
+	"insight/data"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Database 将注入的数据库连接绑定到请求上下文，db 为 nil 时使用全局连接
+func Database(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if db != nil {
+			c.Request = c.Request.WithContext(data.WithDB(c.Request.Context(), db))
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,18 @@
+package middleware
+
+import (
+	"insight/internal/pkg/deprecation"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Deprecation 已弃用的路由响应附加 Deprecation、Sunset 与 Link 头，并记录访问量供 insight routes deprecated 统计
+func Deprecation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if policy, ok := deprecation.Lookup(c.Request.Method, c.FullPath()); ok {
+			policy.SetHeaders(c.Writer.Header(), deprecation.Expand(policy.Successor, c.Param))
+			deprecation.Default().Add(c.Request.Method, c.FullPath())
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"insight/internal/pkg/metrics"
+	"insight/internal/pkg/response"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Metrics 按路由模板记录请求次数与耗时，业务码取自统一响应体
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		code := ""
+		if value, ok := c.Get(response.CodeKey); ok {
+			code = strconv.Itoa(value.(int))
+		}
+		metrics.ObserveHTTP(c.Request.Method, c.FullPath(), c.Writer.Status(), code, time.Since(start))
+	}
+}
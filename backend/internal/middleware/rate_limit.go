@@ -0,0 +1,106 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"insight/config"
+	"insight/internal/pkg/cache"
+	e "insight/internal/pkg/errors"
+	log "insight/internal/pkg/logger"
+	"insight/internal/pkg/ratelimit"
+	"insight/internal/pkg/response"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const APIKeyHeader = "X-API-Key"
+
+var limiter = sync.OnceValue(func() ratelimit.Limiter {
+	if config.GetConfig().RateLimit.Backend == "cache" {
+		return ratelimit.NewSlidingWindow(cache.Default())
+	}
+	return ratelimit.NewMemory()
+})
+
+// RateLimit 按 rate_limit.policies 中的同名策略限流，策略不存在或未开启时不限制。
+// 按用户计数的策略需放在 AdminAuthHandler 之后。
+func RateLimit(policy string) gin.HandlerFunc {
+	cfg := config.GetConfig().RateLimit
+	p, ok := cfg.Policies[policy]
+	if !cfg.Enable || !ok || p.Limit <= 0 || p.Period <= 0 {
+		return func(c *gin.Context) { c.Next() }
+	}
+	limit := ratelimit.Limit{Limit: p.Limit, Period: time.Duration(p.Period) * time.Second, Burst: p.Burst}
+	policyHeader := fmt.Sprintf("%d;w=%d", p.Limit, p.Period)
+	apiKeys := apiKeyHashes(cfg.APIKeys)
+
+	return func(c *gin.Context) {
+		key := policy + ":" + rateLimitKey(c, p.By, apiKeys)
+		result, err := limiter().Allow(c.Request.Context(), key, limit)
+		if err != nil {
+			// 限流后端不可用时放行，避免缓存故障导致整个服务不可用
+			log.WithContext(c.Request.Context()).Warn("Rate limiter unavailable", zap.String("policy", policy), zap.Error(err))
+			c.Next()
+			return
+		}
+
+		c.Header("RateLimit-Policy", policyHeader)
+		c.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("RateLimit-Reset", ceilSeconds(result.Reset))
+		if !result.Allowed {
+			c.Header("Retry-After", ceilSeconds(result.RetryAfter))
+			response.Resp().SetHttpCode(http.StatusTooManyRequests).FailCode(c, e.TooManyRequests)
+			return
+		}
+		c.Next()
+	}
+}
+
+// rateLimitKey 返回计数维度对应的标识，无法识别用户、客户端证书或 API Key 时按 IP 计数
+func rateLimitKey(c *gin.Context, by string, apiKeys map[string]bool) string {
+	switch by {
+	case "user":
+		if uid, ok := c.Get("uid"); ok {
+			return fmt.Sprintf("user:%v", uid)
+		}
+	case "client":
+		if identity, ok := c.Get(ClientIdentityKey); ok {
+			return fmt.Sprintf("client:%v", identity)
+		}
+	case "api_key":
+		// 只识别配置中的 API Key，否则客户端每次更换 Key 即可获得新的配额
+		if apiKey := c.GetHeader(APIKeyHeader); apiKey != "" {
+			if hash := hashAPIKey(apiKey); apiKeys[hash] {
+				return "api_key:" + hash
+			}
+		}
+	}
+	return "ip:" + c.ClientIP()
+}
+
+// apiKeyHashes 返回配置的 API Key 摘要集合
+func apiKeyHashes(keys []string) map[string]bool {
+	hashes := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		hashes[hashAPIKey(key)] = true
+	}
+	return hashes
+}
+
+// hashAPIKey 不在缓存键中保存 API Key 原文
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:16])
+}
+
+func ceilSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimitKey(t *testing.T) {
+	apiKeys := apiKeyHashes([]string{"known-key"})
+	key := func(by, apiKey string) string {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+		if apiKey != "" {
+			c.Request.Header.Set(APIKeyHeader, apiKey)
+		}
+		return rateLimitKey(c, by, apiKeys)
+	}
+
+	assert.Equal(t, "api_key:"+hashAPIKey("known-key"), key("api_key", "known-key"))
+	assert.NotContains(t, key("api_key", "known-key"), "known-key", "API keys should not be stored in plain text")
+	// 未配置的 Key 按 IP 计数，更换 Key 不会获得新的配额
+	assert.Equal(t, "ip:192.0.2.1", key("api_key", "random-key"))
+	assert.Equal(t, "ip:192.0.2.1", key("api_key", ""))
+	assert.Equal(t, "ip:192.0.2.1", key("user", ""))
+}
@@ -0,0 +1,15 @@
+package middleware
+
+import (
+	"insight/data"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReadYourWrites 请求内一旦发生写操作，后续使用请求上下文的读操作都走主库
+func ReadYourWrites() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Request = c.Request.WithContext(data.WithReadYourWrites(c.Request.Context()))
+		c.Next()
+	}
+}
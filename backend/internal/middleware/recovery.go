@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"insight/config"
+	e "insight/internal/pkg/errors"
+	log "insight/internal/pkg/logger"
+	"insight/internal/pkg/response"
+	"net/http"
+	"runtime/debug"
+	"strings"
+	"syscall"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// Recovery 恢复 handler 中的 panic，记录堆栈并以统一响应格式返回 ServerError，
+// system.debug 开启时在 data 中返回 panic 信息与堆栈
+func Recovery() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			// http.ErrAbortHandler 用于主动中断响应，按约定继续抛出
+			if recovered == http.ErrAbortHandler {
+				panic(recovered)
+			}
+
+			err, ok := recovered.(error)
+			if !ok {
+				err = fmt.Errorf("%v", recovered)
+			}
+			stack := debug.Stack()
+			ctx := c.Request.Context()
+			log.WithContext(ctx).Error("Panic recovered",
+				zap.Error(err),
+				zap.String("path", c.Request.URL.Path),
+				zap.ByteString("stack", stack),
+			)
+			span := trace.SpanFromContext(ctx)
+			span.RecordError(err, trace.WithStackTrace(true))
+			span.SetStatus(codes.Error, err.Error())
+			_ = c.Error(err)
+
+			// 客户端已断开时无法再写入响应
+			if brokenPipe(err) {
+				c.Abort()
+				return
+			}
+			resp := response.Resp().SetHttpCode(http.StatusInternalServerError)
+			if config.GetConfig().System.Debug {
+				resp.WithData(gin.H{
+					"panic": err.Error(),
+					"stack": strings.Split(strings.TrimSpace(string(stack)), "\n"),
+				})
+			}
+			resp.FailCode(c, e.ServerError)
+		}()
+		c.Next()
+	}
+}
+
+func brokenPipe(err error) bool {
+	return errors.Is(err, syscall.EPIPE) || errors.Is(err, syscall.ECONNRESET)
+}
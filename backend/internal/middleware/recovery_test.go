@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"encoding/json"
+	"insight/config"
+	e "insight/internal/pkg/errors"
+	log "insight/internal/pkg/logger"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func newRecoveryRouter(t *testing.T, debug bool, recovered any) (*gin.Engine, *observer.ObservedLogs) {
+	gin.SetMode(gin.TestMode)
+	cfg := config.Defaults()
+	cfg.System.Debug = debug
+	config.SetConfig(cfg)
+
+	core, logs := observer.New(zap.ErrorLevel)
+	origLogger := log.Logger
+	log.Logger = zap.New(core)
+	t.Cleanup(func() { log.Logger = origLogger })
+
+	r := gin.New()
+	r.Use(RequestID(), Recovery())
+	r.GET("/panic", func(*gin.Context) { panic(recovered) })
+	return r, logs
+}
+
+func TestRecovery(t *testing.T) {
+	for _, debug := range []bool{false, true} {
+		r, logs := newRecoveryRouter(t, debug, "boom")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/panic", nil))
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		var body map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.ElementsMatch(t, []string{"code", "msg", "data", "cost", "request_id"}, slices.Collect(maps.Keys(body)))
+		assert.EqualValues(t, e.ServerError, body["code"])
+		assert.NotEmpty(t, body["msg"])
+		assert.Equal(t, w.Header().Get(RequestIDHeader), body["request_id"])
+		assert.Equal(t, 1, logs.FilterMessage("Panic recovered").Len())
+
+		if !debug {
+			assert.Nil(t, body["data"], "Panic details should be hidden when debug is off")
+			continue
+		}
+		data, ok := body["data"].(map[string]any)
+		require.True(t, ok)
+		assert.Equal(t, "boom", data["panic"])
+		assert.NotEmpty(t, data["stack"])
+	}
+}
+
+func TestRecoveryAbortHandler(t *testing.T) {
+	r, logs := newRecoveryRouter(t, true, http.ErrAbortHandler)
+	w := httptest.NewRecorder()
+	// http.ErrAbortHandler 交给 net/http 处理，不写入响应
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/panic", nil))
+	})
+	assert.Empty(t, w.Body.String())
+	assert.Zero(t, logs.Len())
+}
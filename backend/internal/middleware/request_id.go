@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	log "insight/internal/pkg/logger"
+	"insight/internal/pkg/response"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const RequestIDHeader = "X-Request-ID"
+
+// RequestID 沿用上游传入的 X-Request-ID，没有或不合法时生成新的 ID，
+// 并在请求 ctx 中放入带 request_id、method、route 的日志对象
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !validRequestID(id) {
+			id = uuid.NewString()
+		}
+		c.Set(response.RequestIDKey, id)
+		c.Header(RequestIDHeader, id)
+
+		fields := []zap.Field{zap.String("request_id", id), zap.String("method", c.Request.Method)}
+		if route := c.FullPath(); route != "" {
+			fields = append(fields, zap.String("route", route))
+		}
+		ctx := log.NewContext(c.Request.Context(), log.Logger.With(fields...))
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// validRequestID 只接受长度有限的可见 ASCII 字符，避免日志注入
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 128 {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
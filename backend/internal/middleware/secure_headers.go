@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"insight/config"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SecureHeaders 设置常用的安全响应头，HTTPS 请求按 tls.hsts 配置附加 Strict-Transport-Security
+func SecureHeaders() gin.HandlerFunc {
+	hsts := config.GetConfig().TLS.HSTS
+	var hstsValue string
+	if hsts.MaxAge > 0 {
+		hstsValue = "max-age=" + strconv.Itoa(hsts.MaxAge)
+		if hsts.IncludeSubdomains {
+			hstsValue += "; includeSubDomains"
+		}
+		if hsts.Preload {
+			hstsValue += "; preload"
+		}
+	}
+
+	return func(c *gin.Context) {
+		header := c.Writer.Header()
+		header.Set("X-Content-Type-Options", "nosniff")
+		header.Set("X-Frame-Options", "DENY")
+		header.Set("Referrer-Policy", "strict-origin-when-cross-origin")
+		// 明文请求中的 HSTS 会被浏览器忽略，由反向代理终止 TLS 时应在代理上设置
+		if hstsValue != "" && c.Request.TLS != nil {
+			header.Set("Strict-Transport-Security", hstsValue)
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	e "insight/internal/pkg/errors"
+	log "insight/internal/pkg/logger"
+	"insight/internal/pkg/response"
+	"insight/internal/service/admin_auth"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// SuperAdmin 只允许超级管理员访问，需在 AdminAuthHandler 之后使用
+func SuperAdmin(users *admin_auth.AdminUserService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ok, err := users.IsSuperAdmin(c.Request.Context(), c.GetUint("uid"))
+		if err != nil {
+			log.WithContext(c.Request.Context()).Error("Failed to check super admin role", zap.Uint("user_id", c.GetUint("uid")), zap.Error(err))
+			response.FailCode(c, e.ServerError)
+			return
+		}
+		if !ok {
+			response.FailCode(c, e.AuthorizationError)
+			return
+		}
+		c.Next()
+	}
+}
@@ -1,21 +1,37 @@
 package model
 
 import (
+	"context"
+	"insight/internal/pkg/query"
+
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/datatypes"
+	"gorm.io/gorm"
 )
 
 type AdminUser struct {
 	ContainsDeleteBaseModel
-	IsAdmin  int8           `json:"is_admin"` // 是否是管理员
-	NickName string         `json:"nickname"` // 昵称
-	Username string         `json:"username"` // 用户名
-	Password string         `json:"password"` // 密码
-	Email    string         `json:"email"`    // 邮箱
-	Mobile   string         `json:"mobile"`   // 手机号
-	Roles    datatypes.JSON `json:"roles"`    // 角色
-	Avatar   string         `json:"avatar"`   // 头像
-	Status   int8           `json:"status"`   // 状态
+	IsAdmin  int8           `gorm:"not null;default:0" json:"is_admin"` // 是否是管理员
+	NickName string         `gorm:"size:64" json:"nickname"`            // 昵称
+	Username string         `gorm:"size:64;not null" json:"username"`   // 用户名
+	Password string         `gorm:"size:255;not null" json:"-"`         // 密码
+	Email    string         `gorm:"size:128" json:"email"`              // 邮箱
+	Mobile   string         `gorm:"size:32" json:"mobile"`              // 手机号
+	Roles    datatypes.JSON `json:"roles"`                              // 角色
+	Avatar   string         `gorm:"size:255" json:"avatar"`             // 头像
+	Status   int8           `gorm:"not null;default:1" json:"status"`   // 状态
+}
+
+// AdminUserQuery 用户列表允许过滤与排序的字段
+var AdminUserQuery = query.Schema{
+	"id":         {Ops: []query.Op{query.Eq, query.In}, Sortable: true},
+	"username":   {Ops: []query.Op{query.Eq, query.Like}, Sortable: true},
+	"nickname":   {Column: "nick_name", Ops: []query.Op{query.Eq, query.Like}},
+	"email":      {Ops: []query.Op{query.Eq, query.Like}},
+	"mobile":     {Ops: []query.Op{query.Eq, query.Like}},
+	"status":     {Ops: []query.Op{query.Eq}},
+	"is_admin":   {Ops: []query.Op{query.Eq}},
+	"created_at": {Ops: []query.Op{query.Gte, query.Lte, query.Between}, Sortable: true},
 }
 
 func NewAdminUsers() *AdminUser {
@@ -27,18 +43,18 @@ func (m *AdminUser) TableName() string {
 	return "a_admin_user"
 }
 
-// GetUserById 根据uid获取用户信息
-func (m *AdminUser) GetUserById(id uint) *AdminUser {
-	if err := m.DB().First(m, id).Error; err != nil {
-		return nil
+// GetUserById 根据uid获取用户信息，用户不存在时返回 gorm.ErrRecordNotFound
+func (m *AdminUser) GetUserById(ctx context.Context, id uint) (*AdminUser, error) {
+	if err := m.DB(ctx).First(m, id).Error; err != nil {
+		return nil, err
 	}
-	return m
+	return m, nil
 }
 
 // Register 用户注册，写入到DB
-func (m *AdminUser) Register() error {
+func (m *AdminUser) Register(ctx context.Context) error {
 	m.Password, _ = m.PasswordHash(m.Password)
-	result := m.DB().Create(m)
+	result := m.DB(ctx).Create(m)
 	return result.Error
 }
 
@@ -56,16 +72,31 @@ func (m *AdminUser) ComparePasswords(password string) bool {
 	return true
 }
 
+// Update 按版本号更新用户，版本号不一致时返回 ErrVersionConflict
+func (m *AdminUser) Update(ctx context.Context, id, version uint, data map[string]any) error {
+	return m.UpdateWithVersion(ctx, m, id, version, data)
+}
+
 // ChangePassword 修改密码
-func (m *AdminUser) ChangePassword() error {
+func (m *AdminUser) ChangePassword(ctx context.Context) error {
 	m.Password, _ = m.PasswordHash(m.Password)
-	return m.DB(m).Update("password", m.Password).Error
+	return m.DB(ctx, m).Updates(map[string]any{"password": m.Password, "version": gorm.Expr("version + 1")}).Error
 }
 
 // GetUserInfo 根据名称获取用户信息
-func (m *AdminUser) GetUserInfo(username string) *AdminUser {
-	if err := m.DB().Where("username", username).First(m).Error; err != nil {
+func (m *AdminUser) GetUserInfo(ctx context.Context, username string) *AdminUser {
+	if err := m.DB(ctx).Where("username", username).First(m).Error; err != nil {
 		return nil
 	}
 	return m
 }
+
+// ListPage 分页
+func (m *AdminUser) ListPage(ctx context.Context, q *query.Query) ([]*AdminUser, *query.Result, error) {
+	var rows []*AdminUser
+	result, err := query.Find(m.DB(ctx, m), q, &rows)
+	if err != nil {
+		return nil, nil, err
+	}
+	return rows, result, nil
+}
@@ -1,41 +1,60 @@
 package model
 
 import (
+	"context"
+	"errors"
 	"insight/data"
-	"insight/internal/global"
+	"insight/internal/pkg/query"
 	"insight/internal/pkg/utils"
+	"time"
 
 	"gorm.io/gorm"
 	"gorm.io/plugin/soft_delete"
 )
 
+// ErrVersionConflict 记录已被修改，版本号不一致
+var ErrVersionConflict = errors.New("version conflict")
+
 type BaseModel struct {
-	ID        uint             `gorm:"column:id;type:int(11) unsigned AUTO_INCREMENT;not null;primarykey" json:"id"`
-	CreatedAt utils.FormatDate `gorm:"column:created_at;type:timestamp;<-:create" json:"created_at"`
-	UpdatedAt utils.FormatDate `gorm:"column:updated_at;type:timestamp" json:"updated_at"`
+	ID        uint             `gorm:"column:id;primarykey" json:"id"`
+	CreatedAt utils.FormatDate `gorm:"column:created_at;<-:create" json:"created_at"`
+	UpdatedAt utils.FormatDate `gorm:"column:updated_at" json:"updated_at"`
+	Version   uint             `gorm:"column:version;not null;default:1" json:"version"` // 乐观锁版本号，每次更新加一
 }
 
-func (m *BaseModel) DB(model ...any) *gorm.DB {
-	return DB(model...)
+func (m *BaseModel) DB(ctx context.Context, model ...any) *gorm.DB {
+	return DB(ctx, model...)
 }
 
+// Paginate 偏移分页，page 从 1 开始
 func (m *BaseModel) Paginate(page, pageSize int) func(db *gorm.DB) *gorm.DB {
-	return func(db *gorm.DB) *gorm.DB {
-		offset := 0
-		limit := global.PerPage
-		if page < 1 {
-			offset = page - 1
-		}
-		if pageSize > 0 {
-			limit = pageSize
-		}
-
-		return db.Offset(offset * limit).Limit(limit)
+	return query.Paginate(page, pageSize)
+}
+
+// UpdateWithVersion 仅在版本号一致时更新并将版本号加一，否则返回 ErrVersionConflict
+func (m *BaseModel) UpdateWithVersion(ctx context.Context, model any, id, version uint, data map[string]any) error {
+	values := make(map[string]any, len(data)+1)
+	for k, v := range data {
+		values[k] = v
+	}
+	values["version"] = gorm.Expr("version + 1")
+	result := m.DB(ctx, model).Where("id = ? AND version = ?", id, version).Updates(values)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrVersionConflict
 	}
+	return nil
 }
 
-func (m *BaseModel) Count(model any, condition string, args []any) (count int64, err error) {
-	query := m.DB(model)
+// NextVersion 整体保存前将版本号加一，使持有旧版本号的更新失败
+func (m *BaseModel) NextVersion() {
+	m.Version++
+}
+
+func (m *BaseModel) Count(ctx context.Context, model any, condition string, args []any) (count int64, err error) {
+	query := m.DB(ctx, model)
 	if condition != "" {
 		query = query.Where(condition, args...)
 	}
@@ -48,12 +67,22 @@ func (m *BaseModel) Count(model any, condition string, args []any) (count int64,
 
 type ContainsDeleteBaseModel struct {
 	BaseModel
-	DeletedAt soft_delete.DeletedAt `gorm:"column:deleted_at;type:int(11) unsigned;not null;default:0;index;" json:"-"`
+	DeletedAt soft_delete.DeletedAt `gorm:"column:deleted_at;not null;default:0;index" json:"-"`
+}
+
+// DeletedTime 删除时间，未删除时为零值
+func (m *ContainsDeleteBaseModel) DeletedTime() time.Time {
+	if m.DeletedAt == 0 {
+		return time.Time{}
+	}
+	return time.Unix(int64(m.DeletedAt), 0)
 }
 
-func DB(model ...any) *gorm.DB {
+// DB 返回绑定上下文的数据库连接，上下文处于 data.Transaction 中时返回该事务
+func DB(ctx context.Context, model ...any) *gorm.DB {
+	db := data.FromContext(ctx)
 	if model != nil {
-		return data.MysqlDB.Model(model[0])
+		return db.Model(model[0])
 	}
-	return data.MysqlDB
+	return db
 }
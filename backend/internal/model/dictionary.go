@@ -0,0 +1,21 @@
+package model
+
+// Dictionary 数据字典表
+type Dictionary struct {
+	ContainsDeleteBaseModel
+	Type   string `gorm:"size:64;not null" json:"type"`     // 字典类型
+	Label  string `gorm:"size:64;not null" json:"label"`    // 显示名称
+	Value  string `gorm:"size:128;not null" json:"value"`   // 字典值
+	Remark string `gorm:"size:255" json:"remark"`           // 备注
+	Status int8   `gorm:"not null;default:1" json:"status"` // 状态
+	Sort   int32  `gorm:"not null;default:0" json:"sort"`   // 排序
+}
+
+func NewDictionary() *Dictionary {
+	return &Dictionary{}
+}
+
+// TableName 获取表名
+func (m *Dictionary) TableName() string {
+	return "dictionaries"
+}
@@ -0,0 +1,23 @@
+package model
+
+// Menu 菜单表
+type Menu struct {
+	ContainsDeleteBaseModel
+	ParentID  uint   `gorm:"not null;default:0;index" json:"parent_id"` // 父级菜单ID
+	Name      string `gorm:"size:64;not null" json:"name"`              // 菜单标识
+	Title     string `gorm:"size:64;not null" json:"title"`             // 菜单标题
+	Path      string `gorm:"size:255" json:"path"`                      // 路由地址
+	Component string `gorm:"size:255" json:"component"`                 // 前端组件
+	Icon      string `gorm:"size:64" json:"icon"`                       // 图标
+	Status    int8   `gorm:"not null;default:1" json:"status"`          // 状态
+	Sort      int32  `gorm:"not null;default:0" json:"sort"`            // 排序
+}
+
+func NewMenu() *Menu {
+	return &Menu{}
+}
+
+// TableName 获取表名
+func (m *Menu) TableName() string {
+	return "menus"
+}
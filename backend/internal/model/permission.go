@@ -1,22 +1,35 @@
 package model
 
 import (
-	"insight/internal/resources"
+	"context"
+	"insight/internal/pkg/query"
 
+	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
 // Permission 权限路由表
 type Permission struct {
 	ContainsDeleteBaseModel
-	Name     string `json:name`      // 权限名称
-	Desc     string `json:desc`      // 权限描述
-	Method   string `json:method`    // 请求方法
-	Route    string `json:route`     // 请求路由
-	Func     string `json:func`      // 接口方法
-	FuncPath string `json:func_path` // 接口方法路径
-	IsAuth   int8   `json:is_auth`   // 是否需要认证
-	Sort     int32  `json:sort`      // 排序
+	Name     string `gorm:"size:60;not null" json:"name"`      // 权限名称
+	Desc     string `gorm:"size:255" json:"desc"`              // 权限描述
+	Method   string `gorm:"size:16;not null" json:"method"`    // 请求方法
+	Route    string `gorm:"size:255;not null" json:"route"`    // 请求路由
+	Func     string `gorm:"size:128" json:"func"`              // 接口方法
+	FuncPath string `gorm:"size:255" json:"func_path"`         // 接口方法路径
+	IsAuth   int8   `gorm:"not null;default:0" json:"is_auth"` // 是否需要认证
+	Sort     int32  `gorm:"not null;default:0" json:"sort"`    // 排序
+}
+
+// PermissionQuery 权限列表允许过滤与排序的字段
+var PermissionQuery = query.Schema{
+	"id":         {Ops: []query.Op{query.Eq, query.In}, Sortable: true},
+	"name":       {Ops: []query.Op{query.Eq, query.Like}},
+	"method":     {Ops: []query.Op{query.Eq, query.In}},
+	"route":      {Ops: []query.Op{query.Eq, query.Like}},
+	"is_auth":    {Ops: []query.Op{query.Eq}},
+	"sort":       {Ops: []query.Op{query.Eq, query.Gte, query.Lte, query.Between}, Sortable: true},
+	"created_at": {Ops: []query.Op{query.Gte, query.Lte, query.Between}, Sortable: true},
 }
 
 func NewPermission() *Permission {
@@ -29,43 +42,49 @@ func (m *Permission) TableName() string {
 }
 
 // Registers 注册接口，写入到DB
-func (m *Permission) Registers(data []map[string]any) error {
-	return m.DB().Clauses(clause.OnConflict{
-		Columns:   []clause.Column{{Name: "route"}, {Name: "deleted_at"}},
-		DoUpdates: clause.AssignmentColumns([]string{"name", "route", "method", "func", "func_path", "updated_at"}),
+func (m *Permission) Registers(ctx context.Context, data []map[string]any) error {
+	return m.DB(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "method"}, {Name: "route"}, {Name: "deleted_at"}},
+		DoUpdates: append(clause.AssignmentColumns([]string{"name", "route", "method", "func", "func_path", "updated_at"}),
+			clause.Assignment{Column: clause.Column{Name: "version"}, Value: gorm.Expr("version + 1")}),
 	}).Model(m).Create(data).Error
 }
 
-// Update 更新权限
-func (m *Permission) Update(id uint, data map[string]any) error {
-	return m.DB().Model(m).Where("id = ?", id).UpdateColumns(data).Error
+// Update 按版本号更新权限，版本号不一致时返回 ErrVersionConflict
+func (m *Permission) Update(ctx context.Context, id, version uint, data map[string]any) error {
+	return m.UpdateWithVersion(ctx, m, id, version, data)
+}
+
+// GetById 根据id获取权限
+func (m *Permission) GetById(ctx context.Context, id uint) (*Permission, error) {
+	if err := m.DB(ctx).Take(m, id).Error; err != nil {
+		return nil, err
+	}
+	return m, nil
 }
 
 // Create 更新权限
-func (m *Permission) Create(data map[string]any) error {
-	return m.DB().Model(m).Create(data).Error
+func (m *Permission) Create(ctx context.Context, data map[string]any) error {
+	return m.DB(ctx).Model(m).Create(data).Error
 }
 
 // HasRoute 判断路由是否存在
-func (m *Permission) HasRoute(route string) (count int64, err error) {
-	count, err = m.Count(m, "route = ?", []any{route})
+func (m *Permission) HasRoute(ctx context.Context, method, route string) (count int64, err error) {
+	count, err = m.Count(ctx, m, "method = ? AND route = ?", []any{method, route})
 	return
 }
 
 // ListPage 分页
-func (m *Permission) ListPage(page, perPage int, condition string, args []any) *resources.PermissionCollection {
-	res := resources.NewPermissionCollection()
-	res.Total, _ = m.Count(m, condition, args)
-	if res.Total == 0 {
-		return res
-	}
-	query := m.DB().Model(m).Scopes(m.Paginate(page, perPage))
-	if condition != "" {
-		query = query.Where(condition, args...)
-	}
-	err := query.Order("sort,id desc").Find(&res.Data).Error
+func (m *Permission) ListPage(ctx context.Context, q *query.Query) ([]*Permission, *query.Result, error) {
+	var rows []*Permission
+	result, err := query.Find(m.DB(ctx, m), q, &rows)
 	if err != nil {
-		return nil
+		return nil, nil, err
 	}
-	return res
+	return rows, result, nil
+}
+
+// beforePurge 永久删除权限前清理角色权限
+func (m *Permission) beforePurge(tx *gorm.DB, ids []uint) error {
+	return tx.Where("permission_id IN ?", ids).Delete(&RolePermission{}).Error
 }
@@ -0,0 +1,55 @@
+package model
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// SuperAdminRole 超级管理员角色标识
+const SuperAdminRole = "super_admin"
+
+// Role 角色表
+type Role struct {
+	ContainsDeleteBaseModel
+	Code   string `gorm:"size:64;not null" json:"code"`     // 角色标识
+	Name   string `gorm:"size:64;not null" json:"name"`     // 角色名称
+	Desc   string `gorm:"size:255" json:"desc"`             // 角色描述
+	Status int8   `gorm:"not null;default:1" json:"status"` // 状态
+	Sort   int32  `gorm:"not null;default:0" json:"sort"`   // 排序
+}
+
+func NewRole() *Role {
+	return &Role{}
+}
+
+// TableName 获取表名
+func (m *Role) TableName() string {
+	return "roles"
+}
+
+// GetByCodes 根据角色标识批量获取角色
+func (m *Role) GetByCodes(ctx context.Context, codes []string) ([]*Role, error) {
+	var roles []*Role
+	if len(codes) == 0 {
+		return roles, nil
+	}
+	err := m.DB(ctx).Where("code IN ?", codes).Order("sort, id").Find(&roles).Error
+	return roles, err
+}
+
+// RolePermission 角色权限关联表
+type RolePermission struct {
+	RoleID       uint `gorm:"primaryKey;autoIncrement:false" json:"role_id"`       // 角色ID
+	PermissionID uint `gorm:"primaryKey;autoIncrement:false" json:"permission_id"` // 权限ID
+}
+
+// TableName 获取表名
+func (m *RolePermission) TableName() string {
+	return "role_permissions"
+}
+
+// beforePurge 永久删除角色前清理角色权限
+func (m *Role) beforePurge(tx *gorm.DB, ids []uint) error {
+	return tx.Where("role_id IN ?", ids).Delete(&RolePermission{}).Error
+}
@@ -0,0 +1,46 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RouteHit 已弃用路由每天的访问量
+type RouteHit struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	Day       string    `gorm:"size:10;not null" json:"day"`    // 日期 2006-01-02
+	Method    string    `gorm:"size:16;not null" json:"method"` // 请求方法
+	Route     string    `gorm:"size:255;not null" json:"route"` // 路由模板
+	Hits      int64     `gorm:"not null;default:0" json:"hits"` // 访问次数
+	UpdatedAt time.Time `json:"updated_at"`                     // 最近一次写入时间
+}
+
+func NewRouteHit() *RouteHit {
+	return &RouteHit{}
+}
+
+// TableName 获取表名
+func (m *RouteHit) TableName() string {
+	return "deprecated_route_hits"
+}
+
+// Increment 累加某天的访问量，记录不存在时创建
+func (m *RouteHit) Increment(ctx context.Context, day, method, route string, hits int64) error {
+	return DB(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "day"}, {Name: "method"}, {Name: "route"}},
+		DoUpdates: clause.Assignments(map[string]any{
+			"hits":       gorm.Expr("hits + ?", hits),
+			"updated_at": time.Now(),
+		}),
+	}).Create(&RouteHit{Day: day, Method: method, Route: route, Hits: hits}).Error
+}
+
+// Since 返回 day 及之后的访问量
+func (m *RouteHit) Since(ctx context.Context, day string) ([]*RouteHit, error) {
+	var hits []*RouteHit
+	err := DB(ctx, m).Where("day >= ?", day).Find(&hits).Error
+	return hits, err
+}
@@ -0,0 +1,142 @@
+package model
+
+import (
+	"context"
+	"insight/internal/pkg/query"
+	"slices"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// TrashQuery 回收站列表允许过滤与排序的字段
+var TrashQuery = query.Schema{
+	"id":         {Ops: []query.Op{query.Eq, query.In}, Sortable: true},
+	"deleted_at": {Ops: []query.Op{query.Gte, query.Lte, query.Between}, Sortable: true},
+}
+
+// Trash 软删除资源的回收站操作
+type Trash interface {
+	// List 分页查询已删除的记录，记录均实现 DeletedTime() time.Time
+	List(ctx context.Context, q *query.Query) ([]any, *query.Result, error)
+	// Restore 恢复一条已删除的记录，记录不存在时返回 gorm.ErrRecordNotFound
+	Restore(ctx context.Context, id uint) error
+	// Purge 永久删除指定的已删除记录，按批删除，出错时返回已删除的数量
+	Purge(ctx context.Context, ids []uint) (int64, error)
+	// PurgeBefore 永久删除在 before 之前删除的记录，按批删除，出错时返回已删除的数量
+	PurgeBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// purger 模型在永久删除前清理关联数据
+type purger interface {
+	beforePurge(tx *gorm.DB, ids []uint) error
+}
+
+var trashes = map[string]Trash{
+	"admin_users":  trashOf[AdminUser]{},
+	"permissions":  trashOf[Permission]{},
+	"roles":        trashOf[Role]{},
+	"menus":        trashOf[Menu]{},
+	"dictionaries": trashOf[Dictionary]{},
+}
+
+// GetTrash 按资源名获取回收站
+func GetTrash(resource string) (Trash, bool) {
+	t, ok := trashes[resource]
+	return t, ok
+}
+
+// TrashResources 支持回收站的资源名
+func TrashResources() []string {
+	names := make([]string, 0, len(trashes))
+	for name := range trashes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// purgeBatchSize 每批永久删除的记录数，避免 IN 条件超出数据库的参数数量限制
+const purgeBatchSize = 1000
+
+type trashOf[T any] struct{}
+
+// deleted 已删除记录的查询，soft_delete 以删除时间戳标记，0 表示未删除
+func (trashOf[T]) deleted(ctx context.Context) *gorm.DB {
+	return DB(ctx).Unscoped().Model(new(T)).Where("deleted_at <> 0")
+}
+
+func (t trashOf[T]) List(ctx context.Context, q *query.Query) ([]any, *query.Result, error) {
+	var rows []*T
+	result, err := query.Find(t.deleted(ctx), q, &rows)
+	if err != nil {
+		return nil, nil, err
+	}
+	data := make([]any, len(rows))
+	for i, row := range rows {
+		data[i] = row
+	}
+	return data, result, nil
+}
+
+func (t trashOf[T]) Restore(ctx context.Context, id uint) error {
+	result := t.deleted(ctx).Where("id = ?", id).UpdateColumn("deleted_at", 0)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (t trashOf[T]) Purge(ctx context.Context, ids []uint) (int64, error) {
+	var total int64
+	for chunk := range slices.Chunk(ids, purgeBatchSize) {
+		var found []uint
+		if err := t.deleted(ctx).Where("id IN ?", chunk).Pluck("id", &found).Error; err != nil {
+			return total, err
+		}
+		affected, err := t.purge(ctx, found)
+		total += affected
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+func (t trashOf[T]) PurgeBefore(ctx context.Context, before time.Time) (int64, error) {
+	var total int64
+	for {
+		var found []uint
+		err := t.deleted(ctx).Where("deleted_at < ?", before.Unix()).Order("id").Limit(purgeBatchSize).Pluck("id", &found).Error
+		if err != nil {
+			return total, err
+		}
+		affected, err := t.purge(ctx, found)
+		total += affected
+		if err != nil || len(found) < purgeBatchSize {
+			return total, err
+		}
+	}
+}
+
+func (t trashOf[T]) purge(ctx context.Context, ids []uint) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	var affected int64
+	err := DB(ctx).Transaction(func(tx *gorm.DB) error {
+		if p, ok := any(new(T)).(purger); ok {
+			if err := p.beforePurge(tx, ids); err != nil {
+				return err
+			}
+		}
+		result := tx.Unscoped().Where("id IN ? AND deleted_at <> 0", ids).Delete(new(T))
+		affected = result.RowsAffected
+		return result.Error
+	})
+	return affected, err
+}
@@ -0,0 +1,106 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"insight/config"
+	"insight/internal/pkg/metrics"
+	pb "insight/proto"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+var (
+	conn    *grpc.ClientConn
+	client  pb.GreeterClient
+	timeout time.Duration
+	mu      sync.Mutex
+)
+
+// Init 创建到 Python agent 的 gRPC 连接，连接在首次调用时建立，agent 未启动不影响服务启动
+func Init() error {
+	mu.Lock()
+	defer mu.Unlock()
+	if conn != nil {
+		return nil
+	}
+
+	cfg := config.GetConfig().Agent
+	c, err := grpc.NewClient(cfg.Addr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithChainUnaryInterceptor(metrics.UnaryClientInterceptor()),
+		// 创建客户端 span 并通过 metadata 传递 W3C traceparent
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+	)
+	if err != nil {
+		return err
+	}
+	conn = c
+	client = pb.NewGreeterClient(c)
+	timeout = time.Duration(cfg.Timeout) * time.Second
+	return nil
+}
+
+// Conn 返回 gRPC 连接，未初始化时为 nil
+func Conn() *grpc.ClientConn {
+	mu.Lock()
+	defer mu.Unlock()
+	return conn
+}
+
+// Close 关闭 gRPC 连接
+func Close() error {
+	mu.Lock()
+	defer mu.Unlock()
+	if conn == nil {
+		return nil
+	}
+	err := conn.Close()
+	conn, client = nil, nil
+	return err
+}
+
+// SayHello 调用 agent 的 Greeter 服务
+func SayHello(ctx context.Context, name string, age int32) (*pb.HelloReply, error) {
+	if err := Init(); err != nil {
+		return nil, err
+	}
+	mu.Lock()
+	c, t := client, timeout
+	mu.Unlock()
+	if c == nil {
+		return nil, grpc.ErrClientConnClosing
+	}
+
+	if t > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, t)
+		defer cancel()
+	}
+	return c.SayHello(ctx, &pb.HelloRequest{Name: name, Age: age})
+}
+
+// Health 通过 gRPC 健康检查协议确认 agent 处于 SERVING 状态
+func Health(ctx context.Context) error {
+	if err := Init(); err != nil {
+		return err
+	}
+	c := Conn()
+	if c == nil {
+		return grpc.ErrClientConnClosing
+	}
+
+	resp, err := healthpb.NewHealthClient(c).Check(ctx, &healthpb.HealthCheckRequest{})
+	if err != nil {
+		return err
+	}
+	if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		return fmt.Errorf("agent status %s", resp.GetStatus())
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"insight/config"
+	"insight/config/autoload"
+	"sync"
+	"time"
+)
+
+// ErrMiss 缓存未命中或已过期
+var ErrMiss = errors.New("cache: miss")
+
+// Store 缓存后端，值为序列化后的字节
+// ttl <= 0 时使用配置的默认过期时间，默认过期时间为 0 表示永不过期
+type Store interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration, tags ...string) error
+	Delete(ctx context.Context, keys ...string) error
+	// InvalidateTags 删除打上任一标签的所有键
+	InvalidateTags(ctx context.Context, tags ...string) error
+	// Incr 将计数加 1 并返回新值，键不存在时从 0 开始并设置 ttl，已存在的键不改变过期时间
+	Incr(ctx context.Context, key string, ttl time.Duration) (int64, error)
+	// IncrWindow 滑动窗口计数，previous*weight + current + 1 <= limit 时才将 current 加 1，
+	// 返回两个窗口的计数以及本次是否计入，判断与计数是原子的
+	IncrWindow(ctx context.Context, current, previous string, weight float64, limit int64, ttl time.Duration) (int64, int64, bool, error)
+	Ping(ctx context.Context) error
+	Close() error
+}
+
+var (
+	store Store
+	once  sync.Once
+)
+
+// InitCache 按配置初始化默认缓存
+func InitCache() {
+	once.Do(func() {
+		s, err := New(config.GetConfig().Cache)
+		if err != nil {
+			panic("failed to init cache, err: " + err.Error())
+		}
+		store = s
+	})
+}
+
+// Default 返回默认缓存，未初始化时按配置初始化
+func Default() Store {
+	InitCache()
+	return store
+}
+
+// Instance 返回已初始化的默认缓存，InitCache 之前为 nil，只注册路由的命令不会因此连接缓存
+func Instance() Store {
+	return store
+}
+
+// New 按配置创建缓存
+func New(cfg autoload.CacheConfig) (Store, error) {
+	defaultTTL := time.Duration(cfg.DefaultTTL) * time.Second
+	switch cfg.Driver {
+	case "", "memory":
+		return NewMemory(cfg.Memory.Capacity, defaultTTL), nil
+	case "redis":
+		return NewRedis(cfg.Redis, cfg.Prefix, defaultTTL)
+	default:
+		return nil, fmt.Errorf("unsupported cache driver %q", cfg.Driver)
+	}
+}
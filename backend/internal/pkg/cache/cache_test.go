@@ -0,0 +1,197 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"insight/config/autoload"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRedis(t *testing.T) (*Redis, *miniredis.Miniredis) {
+	server := miniredis.RunT(t)
+	r, err := NewRedis(autoload.RedisConfig{Addr: server.Addr()}, "insight:", 0)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = r.Close() })
+	return r, server
+}
+
+// 两种实现共用的行为测试
+func testStore(t *testing.T, store Store) {
+	ctx := context.Background()
+
+	_, err := store.Get(ctx, "missing")
+	assert.ErrorIs(t, err, ErrMiss)
+
+	require.NoError(t, store.Set(ctx, "a", []byte("1"), time.Minute, "users"))
+	require.NoError(t, store.Set(ctx, "b", []byte("2"), time.Minute, "users", "user:2"))
+	require.NoError(t, store.Set(ctx, "c", []byte("3"), time.Minute))
+
+	b, err := store.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, "1", string(b))
+
+	require.NoError(t, store.Delete(ctx, "a"))
+	_, err = store.Get(ctx, "a")
+	assert.ErrorIs(t, err, ErrMiss)
+
+	require.NoError(t, store.InvalidateTags(ctx, "user:2"))
+	_, err = store.Get(ctx, "b")
+	assert.ErrorIs(t, err, ErrMiss, "Tagged keys should be invalidated")
+	_, err = store.Get(ctx, "c")
+	assert.NoError(t, err, "Untagged keys should survive")
+
+	for want := int64(1); want <= 2; want++ {
+		n, err := store.Incr(ctx, "counter", time.Minute)
+		require.NoError(t, err)
+		assert.Equal(t, want, n)
+	}
+	b, err = store.Get(ctx, "counter")
+	require.NoError(t, err)
+	assert.Equal(t, "2", string(b), "Counters should be readable with Get")
+
+	// 上一窗口 2 * 0.5 + 当前窗口计数 + 1 <= 2
+	current, previous, ok, err := store.IncrWindow(ctx, "window", "counter", 0.5, 2, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, []any{int64(1), int64(2), true}, []any{current, previous, ok})
+	current, previous, ok, err = store.IncrWindow(ctx, "window", "counter", 0.5, 2, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, []any{int64(1), int64(2), false}, []any{current, previous, ok}, "Rejected calls should not be counted")
+	current, _, ok, err = store.IncrWindow(ctx, "window", "missing", 0.5, 2, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, []any{int64(2), true}, []any{current, ok})
+
+	assert.NoError(t, store.Ping(ctx))
+}
+
+func TestMemory(t *testing.T) {
+	testStore(t, NewMemory(0, 0))
+}
+
+func TestRedis(t *testing.T) {
+	r, _ := newTestRedis(t)
+	testStore(t, r)
+}
+
+func TestMemoryTTL(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemory(0, time.Minute)
+	now := time.Now()
+	m.now = func() time.Time { return now }
+
+	require.NoError(t, m.Set(ctx, "short", []byte("x"), time.Second))
+	require.NoError(t, m.Set(ctx, "default", []byte("x"), 0))
+
+	now = now.Add(2 * time.Second)
+	_, err := m.Get(ctx, "short")
+	assert.ErrorIs(t, err, ErrMiss)
+	_, err = m.Get(ctx, "default")
+	assert.NoError(t, err)
+
+	now = now.Add(time.Minute)
+	_, err = m.Get(ctx, "default")
+	assert.ErrorIs(t, err, ErrMiss, "Zero ttl should fall back to the default ttl")
+}
+
+func TestMemoryEviction(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemory(2, 0)
+
+	require.NoError(t, m.Set(ctx, "a", []byte("1"), 0, "t"))
+	require.NoError(t, m.Set(ctx, "b", []byte("2"), 0))
+	_, _ = m.Get(ctx, "a")
+	require.NoError(t, m.Set(ctx, "c", []byte("3"), 0))
+
+	assert.Equal(t, 2, m.Len())
+	_, err := m.Get(ctx, "b")
+	assert.ErrorIs(t, err, ErrMiss, "Least recently used entry should be evicted")
+	_, err = m.Get(ctx, "a")
+	assert.NoError(t, err)
+}
+
+func TestRedisTTL(t *testing.T) {
+	ctx := context.Background()
+	r, server := newTestRedis(t)
+
+	require.NoError(t, r.Set(ctx, "k", []byte("v"), time.Second))
+	assert.True(t, server.Exists("insight:k"), "Keys should be prefixed")
+
+	server.FastForward(2 * time.Second)
+	_, err := r.Get(ctx, "k")
+	assert.ErrorIs(t, err, ErrMiss)
+
+	// 计数只在创建时设置过期时间
+	_, err = r.Incr(ctx, "n", time.Second)
+	require.NoError(t, err)
+	server.FastForward(500 * time.Millisecond)
+	_, err = r.Incr(ctx, "n", time.Second)
+	require.NoError(t, err)
+	server.FastForward(600 * time.Millisecond)
+	n, err := r.Incr(ctx, "n", time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+}
+
+func TestTypedGetOrLoad(t *testing.T) {
+	ctx := context.Background()
+	typed := NewTyped[map[string]int](NewMemory(0, 0), time.Minute, "counts")
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	load := func(context.Context) (map[string]int, error) {
+		calls.Add(1)
+		<-release
+		return map[string]int{"n": 1}, nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, err := typed.GetOrLoad(ctx, "k", load)
+			assert.NoError(t, err)
+			assert.Equal(t, 1, v["n"])
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	assert.EqualValues(t, 1, calls.Load(), "Concurrent loads should be collapsed")
+
+	v, err := typed.Get(ctx, "k")
+	require.NoError(t, err)
+	assert.Equal(t, 1, v["n"])
+
+	require.NoError(t, typed.store.InvalidateTags(ctx, "counts"))
+	_, err = typed.Get(ctx, "k")
+	assert.ErrorIs(t, err, ErrMiss)
+
+	_, err = typed.GetOrLoad(ctx, "k", func(context.Context) (map[string]int, error) {
+		return nil, errors.New("boom")
+	})
+	assert.EqualError(t, err, "boom")
+	_, err = typed.Get(ctx, "k")
+	assert.ErrorIs(t, err, ErrMiss, "Failed loads should not be cached")
+}
+
+func TestNew(t *testing.T) {
+	store, err := New(autoload.CacheConfig{})
+	require.NoError(t, err)
+	assert.IsType(t, &Memory{}, store)
+
+	_, err = New(autoload.CacheConfig{Driver: "memcached"})
+	assert.Error(t, err)
+
+	server := miniredis.RunT(t)
+	store, err = New(autoload.CacheConfig{Driver: "redis", Redis: autoload.RedisConfig{Addr: server.Addr()}})
+	require.NoError(t, err)
+	assert.IsType(t, &Redis{}, store)
+	_ = store.Close()
+}
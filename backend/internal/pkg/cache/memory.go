@@ -0,0 +1,219 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const defaultMemoryCapacity = 10000
+
+type memoryEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+	tags      []string
+}
+
+func (e *memoryEntry) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && !now.Before(e.expiresAt)
+}
+
+// Memory 进程内 LRU 缓存
+type Memory struct {
+	mu         sync.Mutex
+	capacity   int
+	defaultTTL time.Duration
+	ll         *list.List
+	items      map[string]*list.Element
+	tags       map[string]map[string]struct{}
+	now        func() time.Time
+}
+
+// NewMemory 创建进程内缓存，capacity <= 0 时使用默认容量
+func NewMemory(capacity int, defaultTTL time.Duration) *Memory {
+	if capacity <= 0 {
+		capacity = defaultMemoryCapacity
+	}
+	return &Memory{
+		capacity:   capacity,
+		defaultTTL: defaultTTL,
+		ll:         list.New(),
+		items:      make(map[string]*list.Element),
+		tags:       make(map[string]map[string]struct{}),
+		now:        time.Now,
+	}
+}
+
+func (m *Memory) Get(_ context.Context, key string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	el, ok := m.items[key]
+	if !ok {
+		return nil, ErrMiss
+	}
+	entry := el.Value.(*memoryEntry)
+	if entry.expired(m.now()) {
+		m.remove(el)
+		return nil, ErrMiss
+	}
+	m.ll.MoveToFront(el)
+	return append([]byte(nil), entry.value...), nil
+}
+
+func (m *Memory) Set(_ context.Context, key string, value []byte, ttl time.Duration, tags ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if el, ok := m.items[key]; ok {
+		m.remove(el)
+	}
+	if ttl <= 0 {
+		ttl = m.defaultTTL
+	}
+	entry := &memoryEntry{key: key, value: append([]byte(nil), value...), tags: tags}
+	if ttl > 0 {
+		entry.expiresAt = m.now().Add(ttl)
+	}
+	m.items[key] = m.ll.PushFront(entry)
+	for _, tag := range tags {
+		if m.tags[tag] == nil {
+			m.tags[tag] = make(map[string]struct{})
+		}
+		m.tags[tag][key] = struct{}{}
+	}
+
+	// 超出容量时淘汰最久未使用的条目
+	for m.ll.Len() > m.capacity {
+		m.remove(m.ll.Back())
+	}
+	return nil
+}
+
+func (m *Memory) Delete(_ context.Context, keys ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, key := range keys {
+		if el, ok := m.items[key]; ok {
+			m.remove(el)
+		}
+	}
+	return nil
+}
+
+func (m *Memory) InvalidateTags(_ context.Context, tags ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, tag := range tags {
+		for key := range m.tags[tag] {
+			if el, ok := m.items[key]; ok {
+				m.remove(el)
+			}
+		}
+		delete(m.tags, tag)
+	}
+	return nil
+}
+
+func (m *Memory) Incr(_ context.Context, key string, ttl time.Duration) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.incr(key, ttl)
+}
+
+func (m *Memory) IncrWindow(_ context.Context, current, previous string, weight float64, limit int64, ttl time.Duration) (int64, int64, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	cur, err := m.counter(current)
+	if err != nil {
+		return 0, 0, false, err
+	}
+	prev, err := m.counter(previous)
+	if err != nil {
+		return 0, 0, false, err
+	}
+	if float64(prev)*weight+float64(cur+1) > float64(limit) {
+		return cur, prev, false, nil
+	}
+	cur, err = m.incr(current, ttl)
+	return cur, prev, err == nil, err
+}
+
+// incr 调用方需持有锁
+func (m *Memory) incr(key string, ttl time.Duration) (int64, error) {
+	if el, ok := m.items[key]; ok {
+		entry := el.Value.(*memoryEntry)
+		if !entry.expired(m.now()) {
+			n, err := strconv.ParseInt(string(entry.value), 10, 64)
+			if err != nil {
+				return 0, err
+			}
+			n++
+			entry.value = strconv.AppendInt(entry.value[:0], n, 10)
+			m.ll.MoveToFront(el)
+			return n, nil
+		}
+		m.remove(el)
+	}
+
+	if ttl <= 0 {
+		ttl = m.defaultTTL
+	}
+	entry := &memoryEntry{key: key, value: []byte("1")}
+	if ttl > 0 {
+		entry.expiresAt = m.now().Add(ttl)
+	}
+	m.items[key] = m.ll.PushFront(entry)
+	for m.ll.Len() > m.capacity {
+		m.remove(m.ll.Back())
+	}
+	return 1, nil
+}
+
+// counter 读取计数，不存在或已过期时为 0，调用方需持有锁
+func (m *Memory) counter(key string) (int64, error) {
+	el, ok := m.items[key]
+	if !ok {
+		return 0, nil
+	}
+	entry := el.Value.(*memoryEntry)
+	if entry.expired(m.now()) {
+		m.remove(el)
+		return 0, nil
+	}
+	return strconv.ParseInt(string(entry.value), 10, 64)
+}
+
+// Len 返回当前条目数，包含尚未清理的过期条目
+func (m *Memory) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.ll.Len()
+}
+
+func (m *Memory) Ping(context.Context) error {
+	return nil
+}
+
+func (m *Memory) Close() error {
+	return nil
+}
+
+func (m *Memory) remove(el *list.Element) {
+	entry := m.ll.Remove(el).(*memoryEntry)
+	delete(m.items, entry.key)
+	for _, tag := range entry.tags {
+		if keys, ok := m.tags[tag]; ok {
+			delete(keys, entry.key)
+			if len(keys) == 0 {
+				delete(m.tags, tag)
+			}
+		}
+	}
+}
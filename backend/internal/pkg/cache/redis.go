@@ -0,0 +1,148 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"insight/config/autoload"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const tagKeyPrefix = "tag:"
+
+// incrScript 计数加 1，首次创建时设置过期时间，保证计数与过期设置的原子性
+var incrScript = redis.NewScript(`
+local n = redis.call("INCR", KEYS[1])
+if n == 1 and tonumber(ARGV[1]) > 0 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+end
+return n
+`)
+
+// incrWindowScript 滑动窗口计数，仅在未超限时加 1，被拒绝的请求不计入
+var incrWindowScript = redis.NewScript(`
+local current = tonumber(redis.call("GET", KEYS[1]) or "0")
+local previous = tonumber(redis.call("GET", KEYS[2]) or "0")
+if previous * tonumber(ARGV[1]) + current + 1 > tonumber(ARGV[2]) then
+	return {current, previous, 0}
+end
+current = redis.call("INCR", KEYS[1])
+if current == 1 and tonumber(ARGV[3]) > 0 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[3])
+end
+return {current, previous, 1}
+`)
+
+// Redis 基于 Redis 协议的缓存
+// 标签以集合保存其下的键，集合不设过期时间，失效标签时一并删除
+type Redis struct {
+	client     *redis.Client
+	prefix     string
+	defaultTTL time.Duration
+}
+
+// NewRedis 创建 Redis 缓存并检查连接
+func NewRedis(cfg autoload.RedisConfig, prefix string, defaultTTL time.Duration) (*Redis, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     cfg.Addr,
+		Username: cfg.Username,
+		Password: cfg.Password,
+		DB:       cfg.DB,
+		PoolSize: cfg.PoolSize,
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		_ = client.Close()
+		return nil, err
+	}
+	return &Redis{client: client, prefix: prefix, defaultTTL: defaultTTL}, nil
+}
+
+func (r *Redis) Get(ctx context.Context, key string) ([]byte, error) {
+	b, err := r.client.Get(ctx, r.key(key)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrMiss
+	}
+	return b, err
+}
+
+func (r *Redis) Set(ctx context.Context, key string, value []byte, ttl time.Duration, tags ...string) error {
+	if ttl <= 0 {
+		ttl = r.defaultTTL
+	}
+	pipe := r.client.TxPipeline()
+	pipe.Set(ctx, r.key(key), value, ttl)
+	for _, tag := range tags {
+		pipe.SAdd(ctx, r.tagKey(tag), key)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (r *Redis) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = r.key(key)
+	}
+	return r.client.Del(ctx, prefixed...).Err()
+}
+
+func (r *Redis) InvalidateTags(ctx context.Context, tags ...string) error {
+	for _, tag := range tags {
+		tagKey := r.tagKey(tag)
+		keys, err := r.client.SMembers(ctx, tagKey).Result()
+		if err != nil {
+			return err
+		}
+		prefixed := make([]string, 0, len(keys)+1)
+		for _, key := range keys {
+			prefixed = append(prefixed, r.key(key))
+		}
+		prefixed = append(prefixed, tagKey)
+		if err := r.client.Del(ctx, prefixed...).Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Redis) Incr(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	if ttl <= 0 {
+		ttl = r.defaultTTL
+	}
+	return incrScript.Run(ctx, r.client, []string{r.key(key)}, ttl.Milliseconds()).Int64()
+}
+
+func (r *Redis) IncrWindow(ctx context.Context, current, previous string, weight float64, limit int64, ttl time.Duration) (int64, int64, bool, error) {
+	if ttl <= 0 {
+		ttl = r.defaultTTL
+	}
+	res, err := incrWindowScript.Run(ctx, r.client, []string{r.key(current), r.key(previous)},
+		strconv.FormatFloat(weight, 'f', -1, 64), limit, ttl.Milliseconds()).Int64Slice()
+	if err != nil {
+		return 0, 0, false, err
+	}
+	return res[0], res[1], res[2] == 1, nil
+}
+
+func (r *Redis) Ping(ctx context.Context) error {
+	return r.client.Ping(ctx).Err()
+}
+
+func (r *Redis) Close() error {
+	return r.client.Close()
+}
+
+func (r *Redis) key(key string) string {
+	return r.prefix + key
+}
+
+func (r *Redis) tagKey(tag string) string {
+	return r.prefix + tagKeyPrefix + tag
+}
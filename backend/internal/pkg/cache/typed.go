@@ -0,0 +1,76 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// Typed 带类型的缓存，值以 JSON 序列化存储
+type Typed[T any] struct {
+	store Store
+	ttl   time.Duration
+	tags  []string
+	group singleflight.Group
+}
+
+// NewTyped 创建带类型的缓存，ttl 与 tags 作用于每次写入
+func NewTyped[T any](store Store, ttl time.Duration, tags ...string) *Typed[T] {
+	return &Typed[T]{store: store, ttl: ttl, tags: tags}
+}
+
+// Get 读取缓存，未命中返回 ErrMiss
+func (t *Typed[T]) Get(ctx context.Context, key string) (T, error) {
+	var value T
+	b, err := t.store.Get(ctx, key)
+	if err != nil {
+		return value, err
+	}
+	if err := json.Unmarshal(b, &value); err != nil {
+		return value, err
+	}
+	return value, nil
+}
+
+// Set 写入缓存，tags 追加在默认标签之后
+func (t *Typed[T]) Set(ctx context.Context, key string, value T, tags ...string) error {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return t.store.Set(ctx, key, b, t.ttl, append(t.tags[:len(t.tags):len(t.tags)], tags...)...)
+}
+
+// Delete 删除缓存
+func (t *Typed[T]) Delete(ctx context.Context, keys ...string) error {
+	return t.store.Delete(ctx, keys...)
+}
+
+// GetOrLoad 读取缓存，未命中时调用 load 加载并回写
+// 同一个键的并发加载只会执行一次
+func (t *Typed[T]) GetOrLoad(ctx context.Context, key string, load func(ctx context.Context) (T, error), tags ...string) (T, error) {
+	if value, err := t.Get(ctx, key); err == nil {
+		return value, nil
+	} else if !errors.Is(err, ErrMiss) {
+		var zero T
+		return zero, err
+	}
+
+	v, err, _ := t.group.Do(key, func() (any, error) {
+		value, err := load(ctx)
+		if err != nil {
+			return value, err
+		}
+		// 回写失败不影响本次读取
+		_ = t.Set(ctx, key, value, tags...)
+		return value, nil
+	})
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return v.(T), nil
+}
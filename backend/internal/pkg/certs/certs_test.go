@@ -0,0 +1,167 @@
+package certs
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"insight/config/autoload"
+	log "insight/internal/pkg/logger"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestMain(m *testing.M) {
+	log.Logger = zap.NewNop()
+	os.Exit(m.Run())
+}
+
+func writeSelfSigned(t *testing.T, dir string) *KeyPair {
+	t.Helper()
+	pair, err := GenerateSelfSigned([]string{"localhost", "127.0.0.1"}, time.Hour)
+	require.NoError(t, err)
+	require.NoError(t, pair.WriteFiles(filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")))
+	return pair
+}
+
+func TestGenerateSelfSigned(t *testing.T) {
+	pair, err := GenerateSelfSigned([]string{"localhost", "127.0.0.1"}, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, "localhost", pair.Cert.Subject.CommonName)
+	assert.Equal(t, []string{"localhost"}, pair.Cert.DNSNames)
+	require.Len(t, pair.Cert.IPAddresses, 1)
+	assert.Equal(t, "127.0.0.1", pair.Cert.IPAddresses[0].String())
+
+	client, err := GenerateClient(pair, "billing", time.Hour)
+	require.NoError(t, err)
+	roots := x509.NewCertPool()
+	roots.AddCert(pair.Cert)
+	_, err = client.Cert.Verify(x509.VerifyOptions{Roots: roots, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}})
+	assert.NoError(t, err, "Client certificate should be signed by the self-signed certificate")
+
+	_, err = GenerateSelfSigned(nil, time.Hour)
+	assert.Error(t, err)
+}
+
+func TestReloader(t *testing.T) {
+	dir := t.TempDir()
+	first := writeSelfSigned(t, dir)
+	r, err := NewReloader(filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem"), 10*time.Second)
+	require.NoError(t, err)
+	now := time.Now()
+	r.now = func() time.Time { return now }
+
+	cert, err := r.GetCertificate(nil)
+	require.NoError(t, err)
+	assert.Equal(t, first.Cert.SerialNumber, cert.Leaf.SerialNumber)
+
+	second := writeSelfSigned(t, dir)
+	later := time.Now().Add(time.Minute)
+	for _, file := range []string{"cert.pem", "key.pem"} {
+		require.NoError(t, os.Chtimes(filepath.Join(dir, file), later, later))
+	}
+	cert, _ = r.GetCertificate(nil)
+	assert.Equal(t, first.Cert.SerialNumber, cert.Leaf.SerialNumber, "Files should not be checked before the interval elapses")
+
+	now = now.Add(10 * time.Second)
+	cert, _ = r.GetCertificate(nil)
+	assert.Equal(t, second.Cert.SerialNumber, cert.Leaf.SerialNumber)
+
+	// 无效的证书不替换当前证书
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "key.pem"), []byte("invalid"), 0o600))
+	later = later.Add(time.Minute)
+	require.NoError(t, os.Chtimes(filepath.Join(dir, "key.pem"), later, later))
+	now = now.Add(10 * time.Second)
+	cert, err = r.GetCertificate(nil)
+	require.NoError(t, err)
+	assert.Equal(t, second.Cert.SerialNumber, cert.Leaf.SerialNumber)
+
+	_, err = NewReloader(filepath.Join(dir, "missing.pem"), filepath.Join(dir, "key.pem"), time.Second)
+	assert.Error(t, err)
+}
+
+func TestServerConfigValidation(t *testing.T) {
+	dir := t.TempDir()
+	writeSelfSigned(t, dir)
+	cfg := autoload.TLSConfig{CertFile: filepath.Join(dir, "cert.pem"), KeyFile: filepath.Join(dir, "key.pem")}
+
+	tlsConfig, err := ServerConfig(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, uint16(tls.VersionTLS12), tlsConfig.MinVersion)
+	assert.Equal(t, tls.NoClientCert, tlsConfig.ClientAuth)
+	assert.Contains(t, tlsConfig.NextProtos, "h2")
+
+	for _, invalid := range []autoload.TLSConfig{
+		{CertFile: cfg.CertFile, KeyFile: cfg.KeyFile, MinVersion: "1.0"},
+		{CertFile: cfg.CertFile, KeyFile: cfg.KeyFile, ClientAuth: "always"},
+		{CertFile: cfg.CertFile, KeyFile: cfg.KeyFile, ClientAuth: "require"},
+		{CertFile: cfg.CertFile, KeyFile: cfg.KeyFile, ClientAuth: "require", ClientCAFile: cfg.KeyFile},
+	} {
+		_, err := ServerConfig(invalid)
+		assert.Error(t, err, "%+v", invalid)
+	}
+}
+
+func TestMutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca := writeSelfSigned(t, dir)
+	tlsConfig, err := ServerConfig(autoload.TLSConfig{
+		CertFile:     filepath.Join(dir, "cert.pem"),
+		KeyFile:      filepath.Join(dir, "key.pem"),
+		ClientAuth:   "optional",
+		ClientCAFile: filepath.Join(dir, "cert.pem"),
+	})
+	require.NoError(t, err)
+
+	identities := map[string]string{"billing-service": "billing"}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	srv := &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			identity, _ := Identity(r.TLS, identities)
+			_, _ = io.WriteString(w, r.Proto+" "+identity)
+		}),
+		TLSConfig: tlsConfig,
+	}
+	go func() { _ = srv.ServeTLS(listener, "", "") }()
+	defer srv.Close()
+	url := "https://" + listener.Addr().String()
+
+	request := func(certificates ...tls.Certificate) string {
+		roots := x509.NewCertPool()
+		roots.AddCert(ca.Cert)
+		client := &http.Client{Transport: &http.Transport{
+			TLSClientConfig:   &tls.Config{RootCAs: roots, Certificates: certificates},
+			ForceAttemptHTTP2: true,
+		}}
+		resp, err := client.Get(url)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return string(body)
+	}
+	clientCert := func(commonName string) tls.Certificate {
+		pair, err := GenerateClient(ca, commonName, time.Hour)
+		require.NoError(t, err)
+		keyPEM, err := pair.KeyPEM()
+		require.NoError(t, err)
+		cert, err := tls.X509KeyPair(pair.CertPEM(), keyPEM)
+		require.NoError(t, err)
+		return cert
+	}
+
+	assert.Equal(t, "HTTP/2.0 ", request())
+	assert.Equal(t, "HTTP/2.0 billing", request(clientCert("Billing-Service")))
+	assert.Equal(t, "HTTP/2.0 ", request(clientCert("unknown")), "Unlisted certificates should not map to an identity")
+
+	identity, ok := Identity(nil, nil)
+	assert.False(t, ok)
+	assert.Empty(t, identity)
+}
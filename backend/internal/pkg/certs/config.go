@@ -0,0 +1,73 @@
+package certs
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"insight/config/autoload"
+	"os"
+	"strings"
+	"time"
+)
+
+// ServerConfig 根据 tls 配置创建 HTTPS 服务使用的 tls.Config，证书文件修改后自动重新加载
+func ServerConfig(cfg autoload.TLSConfig) (*tls.Config, error) {
+	reloader, err := NewReloader(cfg.CertFile, cfg.KeyFile, time.Duration(cfg.ReloadInterval)*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig := &tls.Config{
+		GetCertificate: reloader.GetCertificate,
+		// 优先协商 HTTP/2
+		NextProtos: []string{"h2", "http/1.1"},
+	}
+
+	switch cfg.MinVersion {
+	case "", "1.2":
+		tlsConfig.MinVersion = tls.VersionTLS12
+	case "1.3":
+		tlsConfig.MinVersion = tls.VersionTLS13
+	default:
+		return nil, fmt.Errorf("unsupported TLS min version %q", cfg.MinVersion)
+	}
+
+	switch cfg.ClientAuth {
+	case "", "none":
+		return tlsConfig, nil
+	case "optional":
+		tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+	case "require":
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	default:
+		return nil, fmt.Errorf("unsupported TLS client auth %q", cfg.ClientAuth)
+	}
+	if cfg.ClientCAFile == "" {
+		return nil, fmt.Errorf("tls.client_ca_file is required when client_auth is %q", cfg.ClientAuth)
+	}
+	pem, err := os.ReadFile(cfg.ClientCAFile)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig.ClientCAs = x509.NewCertPool()
+	if !tlsConfig.ClientCAs.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificates found in %s", cfg.ClientCAFile)
+	}
+	return tlsConfig, nil
+}
+
+// Identity 返回已校验的客户端证书对应的身份，identities 为空时身份即证书 CN，否则只接受列出的 CN
+func Identity(state *tls.ConnectionState, identities map[string]string) (string, bool) {
+	if state == nil || len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return "", false
+	}
+	commonName := state.VerifiedChains[0][0].Subject.CommonName
+	if commonName == "" {
+		return "", false
+	}
+	if len(identities) == 0 {
+		return commonName, true
+	}
+	// 配置键经 viper 读取后为小写
+	identity, ok := identities[strings.ToLower(commonName)]
+	return identity, ok
+}
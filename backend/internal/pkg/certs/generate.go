@@ -0,0 +1,124 @@
+package certs
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// KeyPair 生成的证书与私钥
+type KeyPair struct {
+	Cert *x509.Certificate
+	Key  *ecdsa.PrivateKey
+}
+
+// GenerateSelfSigned 生成本地开发用的自签名服务端证书，hosts 可以是域名或 IP，第一个作为 CN。
+// 证书同时可作为 CA 签发客户端证书，用于本地调试 mTLS
+func GenerateSelfSigned(hosts []string, validFor time.Duration) (*KeyPair, error) {
+	if len(hosts) == 0 {
+		return nil, errors.New("at least one host is required")
+	}
+	template, err := newTemplate(hosts[0], validFor)
+	if err != nil {
+		return nil, err
+	}
+	for _, host := range hosts {
+		if ip := net.ParseIP(host); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, host)
+		}
+	}
+	template.IsCA = true
+	template.BasicConstraintsValid = true
+	template.KeyUsage |= x509.KeyUsageCertSign
+	template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth}
+	return sign(template, nil)
+}
+
+// GenerateClient 使用 ca 签发客户端证书，commonName 即客户端身份
+func GenerateClient(ca *KeyPair, commonName string, validFor time.Duration) (*KeyPair, error) {
+	template, err := newTemplate(commonName, validFor)
+	if err != nil {
+		return nil, err
+	}
+	template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}
+	return sign(template, ca)
+}
+
+// CertPEM 返回 PEM 编码的证书
+func (p *KeyPair) CertPEM() []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: p.Cert.Raw})
+}
+
+// KeyPEM 返回 PEM 编码的 PKCS#8 私钥
+func (p *KeyPair) KeyPEM() ([]byte, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(p.Key)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+// WriteFiles 写入证书与私钥，私钥仅所有者可读
+func (p *KeyPair) WriteFiles(certFile, keyFile string) error {
+	keyPEM, err := p.KeyPEM()
+	if err != nil {
+		return err
+	}
+	for _, file := range []string{certFile, keyFile} {
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			return err
+		}
+	}
+	if err := os.WriteFile(certFile, p.CertPEM(), 0o644); err != nil {
+		return err
+	}
+	return os.WriteFile(keyFile, keyPEM, 0o600)
+}
+
+func newTemplate(commonName string, validFor time.Duration) (*x509.Certificate, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	return &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: commonName, Organization: []string{"Insight Development"}},
+		// 容忍客户端时钟偏差
+		NotBefore: now.Add(-time.Hour),
+		NotAfter:  now.Add(validFor),
+		KeyUsage:  x509.KeyUsageDigitalSignature,
+	}, nil
+}
+
+// sign 生成密钥并签发证书，parent 为空时自签名
+func sign(template *x509.Certificate, parent *KeyPair) (*KeyPair, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	issuer, signer := template, key
+	if parent != nil {
+		issuer, signer = parent.Cert, parent.Key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, issuer, &key.PublicKey, signer)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+	return &KeyPair{Cert: cert, Key: key}, nil
+}
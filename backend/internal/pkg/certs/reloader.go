@@ -0,0 +1,86 @@
+package certs
+
+import (
+	"crypto/tls"
+	"fmt"
+	log "insight/internal/pkg/logger"
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Reloader 从文件加载证书，文件修改后在之后的 TLS 握手中使用新证书，无需重启服务
+type Reloader struct {
+	certFile string
+	keyFile  string
+	interval time.Duration
+	now      func() time.Time
+
+	mu        sync.Mutex
+	cert      *tls.Certificate
+	modTime   time.Time
+	checkedAt time.Time
+}
+
+// NewReloader 加载证书，interval 为检查文件修改时间的最小间隔
+func NewReloader(certFile, keyFile string, interval time.Duration) (*Reloader, error) {
+	r := &Reloader{certFile: certFile, keyFile: keyFile, interval: interval, now: time.Now}
+	modTime, err := r.stat()
+	if err != nil {
+		return nil, err
+	}
+	if err := r.load(modTime); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// GetCertificate 用作 tls.Config.GetCertificate，重新加载失败时继续使用旧证书
+func (r *Reloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if now := r.now(); now.Sub(r.checkedAt) >= r.interval {
+		r.checkedAt = now
+		modTime, err := r.stat()
+		if err == nil && !modTime.Equal(r.modTime) {
+			err = r.load(modTime)
+		}
+		// 证书与私钥先后写入时可能暂时不匹配，下次检查时重试
+		if err != nil {
+			log.Logger.Error("Failed to reload TLS certificate", zap.String("cert_file", r.certFile), zap.Error(err))
+		}
+	}
+	return r.cert, nil
+}
+
+// stat 返回证书与私钥中较新的修改时间
+func (r *Reloader) stat() (time.Time, error) {
+	var modTime time.Time
+	for _, file := range []string{r.certFile, r.keyFile} {
+		info, err := os.Stat(file)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if info.ModTime().After(modTime) {
+			modTime = info.ModTime()
+		}
+	}
+	return modTime, nil
+}
+
+func (r *Reloader) load(modTime time.Time) error {
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("load TLS certificate: %w", err)
+	}
+	r.cert = &cert
+	r.modTime = modTime
+	log.Logger.Info("Loaded TLS certificate",
+		zap.String("subject", cert.Leaf.Subject.String()),
+		zap.Time("not_after", cert.Leaf.NotAfter),
+	)
+	return nil
+}
@@ -0,0 +1,48 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock 时间来源，服务通过注入的 Clock 取当前时间，测试中替换为 Fake
+type Clock interface {
+	Now() time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+// Real 系统时钟
+var Real Clock = realClock{}
+
+// Fake 可手动调整的时钟，时间只在调用 Set 或 Advance 时变化
+type Fake struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func NewFake(now time.Time) *Fake {
+	return &Fake{now: now}
+}
+
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+// Set 将时钟设置为 now
+func (f *Fake) Set(now time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = now
+}
+
+// Advance 将时钟向后拨 d
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = f.now.Add(d)
+}
@@ -0,0 +1,25 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFake(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	fake := NewFake(start)
+	assert.Equal(t, start, fake.Now())
+
+	fake.Advance(time.Hour)
+	assert.Equal(t, start.Add(time.Hour), fake.Now())
+
+	fake.Set(start)
+	assert.Equal(t, start, fake.Now())
+}
+
+func TestReal(t *testing.T) {
+	before := time.Now()
+	assert.False(t, Real.Now().Before(before))
+}
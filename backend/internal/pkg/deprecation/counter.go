@@ -0,0 +1,64 @@
+package deprecation
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DayLayout 访问量按天统计的日期格式
+const DayLayout = "2006-01-02"
+
+// Hit 某天某个弃用路由的访问
+type Hit struct {
+	Day    string
+	Method string
+	Path   string
+}
+
+// Counter 在内存中累计弃用路由的访问量，由 Flush 定期写入存储
+type Counter struct {
+	mu     sync.Mutex
+	counts map[Hit]int64
+	now    func() time.Time
+}
+
+func NewCounter() *Counter {
+	return &Counter{counts: map[Hit]int64{}, now: time.Now}
+}
+
+var defaultCounter = NewCounter()
+
+// Default 返回全局的访问计数
+func Default() *Counter {
+	return defaultCounter
+}
+
+// Add 记录一次访问
+func (c *Counter) Add(method, path string) {
+	hit := Hit{Day: c.now().Format(DayLayout), Method: method, Path: path}
+	c.mu.Lock()
+	c.counts[hit]++
+	c.mu.Unlock()
+}
+
+// Flush 将累计的访问量交给 save 写入并清零，写入失败时保留计数等待下次写入
+func (c *Counter) Flush(ctx context.Context, save func(context.Context, map[Hit]int64) error) error {
+	c.mu.Lock()
+	counts := c.counts
+	c.counts = map[Hit]int64{}
+	c.mu.Unlock()
+	if len(counts) == 0 {
+		return nil
+	}
+
+	err := save(ctx, counts)
+	if err != nil {
+		c.mu.Lock()
+		for hit, n := range counts {
+			c.counts[hit] += n
+		}
+		c.mu.Unlock()
+	}
+	return err
+}
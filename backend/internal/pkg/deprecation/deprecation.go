@@ -0,0 +1,87 @@
+package deprecation
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Policy 路由的弃用信息
+type Policy struct {
+	Since     time.Time // 弃用时间
+	Sunset    time.Time // 计划下线时间，零值表示未确定
+	Successor string    // 替代的接口路径，可包含与原路由同名的 :param 参数
+	Link      string    // 迁移说明文档地址
+}
+
+// SetHeaders 按 RFC 9745 与 RFC 8594 设置 Deprecation、Sunset 与 Link 响应头，successor 为替代接口的实际地址
+func (p Policy) SetHeaders(h http.Header, successor string) {
+	h.Set("Deprecation", "@"+strconv.FormatInt(p.Since.Unix(), 10))
+	if !p.Sunset.IsZero() {
+		h.Set("Sunset", p.Sunset.UTC().Format(http.TimeFormat))
+	}
+	if successor != "" {
+		h.Add("Link", "<"+successor+`>; rel="successor-version"`)
+	}
+	if p.Link != "" {
+		h.Add("Link", "<"+p.Link+`>; rel="deprecation"`)
+	}
+}
+
+// Route 已弃用的路由
+type Route struct {
+	Method string
+	Path   string // gin 路由模板，如 /api/admin/permissions/:id
+	Policy
+}
+
+var (
+	mu     sync.RWMutex
+	routes = map[string]Route{}
+)
+
+// Register 标记路由已弃用，path 为包含分组前缀的完整路由模板
+func Register(method, path string, policy Policy) {
+	mu.Lock()
+	defer mu.Unlock()
+	routes[method+" "+path] = Route{Method: method, Path: path, Policy: policy}
+}
+
+// Lookup 返回路由的弃用信息
+func Lookup(method, path string) (Policy, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	route, ok := routes[method+" "+path]
+	return route.Policy, ok
+}
+
+// Routes 按路径与请求方法排序的全部弃用路由
+func Routes() []Route {
+	mu.RLock()
+	defer mu.RUnlock()
+	result := make([]Route, 0, len(routes))
+	for _, route := range routes {
+		result = append(result, route)
+	}
+	slices.SortFunc(result, func(a, b Route) int {
+		if c := strings.Compare(a.Path, b.Path); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Method, b.Method)
+	})
+	return result
+}
+
+// Expand 将替代接口路径中的 :param 与 *param 替换为请求中的参数值
+func Expand(successor string, param func(name string) string) string {
+	segments := strings.Split(successor, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			segments[i] = strings.TrimPrefix(param(segment[1:]), "/")
+		}
+	}
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,91 @@
+package deprecation
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetHeaders(t *testing.T) {
+	policy := Policy{
+		Since:  time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC),
+		Sunset: time.Date(2027, 4, 30, 0, 0, 0, 0, time.UTC),
+		Link:   "https://example.com/migration",
+	}
+	h := http.Header{}
+	policy.SetHeaders(h, "/api/v1/items/1")
+	assert.Equal(t, "@1792368000", h.Get("Deprecation"))
+	assert.Equal(t, "Fri, 30 Apr 2027 00:00:00 GMT", h.Get("Sunset"))
+	assert.Equal(t, []string{
+		`</api/v1/items/1>; rel="successor-version"`,
+		`<https://example.com/migration>; rel="deprecation"`,
+	}, h.Values("Link"))
+
+	h = http.Header{}
+	Policy{Since: policy.Since}.SetHeaders(h, "")
+	assert.Empty(t, h.Get("Sunset"))
+	assert.Empty(t, h.Values("Link"))
+}
+
+func TestExpand(t *testing.T) {
+	params := map[string]string{"id": "42", "path": "/a/b"}
+	param := func(name string) string { return params[name] }
+	assert.Equal(t, "/api/v1/items/42", Expand("/api/v1/items/:id", param))
+	assert.Equal(t, "/api/v1/files/a/b", Expand("/api/v1/files/*path", param))
+	assert.Equal(t, "", Expand("", param))
+}
+
+func TestRegistry(t *testing.T) {
+	Register(http.MethodGet, "/api/test/b", Policy{})
+	Register(http.MethodPost, "/api/test/a", Policy{Successor: "/api/v1/test/a"})
+	Register(http.MethodGet, "/api/test/a", Policy{})
+
+	policy, ok := Lookup(http.MethodPost, "/api/test/a")
+	assert.True(t, ok)
+	assert.Equal(t, "/api/v1/test/a", policy.Successor)
+	_, ok = Lookup(http.MethodDelete, "/api/test/a")
+	assert.False(t, ok)
+
+	var paths []string
+	for _, route := range Routes() {
+		paths = append(paths, route.Method+" "+route.Path)
+	}
+	assert.Equal(t, []string{"GET /api/test/a", "POST /api/test/a", "GET /api/test/b"}, paths)
+}
+
+func TestCounter(t *testing.T) {
+	c := NewCounter()
+	now := time.Date(2026, 10, 19, 23, 59, 0, 0, time.Local)
+	c.now = func() time.Time { return now }
+	c.Add(http.MethodGet, "/api/items")
+	c.Add(http.MethodGet, "/api/items")
+	now = now.Add(time.Minute)
+	c.Add(http.MethodGet, "/api/items")
+
+	// 写入失败时保留计数
+	err := c.Flush(context.Background(), func(context.Context, map[Hit]int64) error { return errors.New("unavailable") })
+	require.Error(t, err)
+	c.Add(http.MethodGet, "/api/items")
+
+	var saved map[Hit]int64
+	require.NoError(t, c.Flush(context.Background(), func(_ context.Context, counts map[Hit]int64) error {
+		saved = counts
+		return nil
+	}))
+	assert.Equal(t, map[Hit]int64{
+		{Day: "2026-10-19", Method: http.MethodGet, Path: "/api/items"}: 2,
+		{Day: "2026-10-20", Method: http.MethodGet, Path: "/api/items"}: 2,
+	}, saved)
+
+	called := false
+	require.NoError(t, c.Flush(context.Background(), func(context.Context, map[Hit]int64) error {
+		called = true
+		return nil
+	}))
+	assert.False(t, called, "Empty counts should not be saved")
+}
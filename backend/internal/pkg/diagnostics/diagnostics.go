@@ -0,0 +1,159 @@
+// Package diagnostics 收集运行时、GC 与数据库连接池状态，用于排查线上内存与连接问题
+package diagnostics
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"net"
+	"runtime"
+	"runtime/debug"
+	"runtime/pprof"
+	"sort"
+	"time"
+)
+
+var startedAt = time.Now()
+
+// RuntimeStats 运行时状态，内存单位为字节
+type RuntimeStats struct {
+	GoVersion    string      `json:"go_version"`
+	StartedAt    time.Time   `json:"started_at"`
+	Uptime       string      `json:"uptime"`
+	NumCPU       int         `json:"num_cpu"`
+	GOMAXPROCS   int         `json:"gomaxprocs"`
+	NumGoroutine int         `json:"num_goroutine"`
+	NumCgoCall   int64       `json:"num_cgo_call"`
+	Memory       MemoryStats `json:"memory"`
+	GC           GCStats     `json:"gc"`
+}
+
+type MemoryStats struct {
+	Alloc        uint64 `json:"alloc"`         // 堆上存活对象占用
+	TotalAlloc   uint64 `json:"total_alloc"`   // 累计分配
+	Sys          uint64 `json:"sys"`           // 从操作系统获取的内存
+	HeapAlloc    uint64 `json:"heap_alloc"`    // 堆上存活对象占用
+	HeapSys      uint64 `json:"heap_sys"`      // 堆从操作系统获取的内存
+	HeapIdle     uint64 `json:"heap_idle"`     // 空闲的堆内存
+	HeapInuse    uint64 `json:"heap_inuse"`    // 使用中的堆内存
+	HeapReleased uint64 `json:"heap_released"` // 已归还操作系统的堆内存
+	HeapObjects  uint64 `json:"heap_objects"`  // 堆上对象数
+	StackInuse   uint64 `json:"stack_inuse"`   // 协程栈占用
+	Mallocs      uint64 `json:"mallocs"`       // 累计分配对象数
+	Frees        uint64 `json:"frees"`         // 累计释放对象数
+}
+
+type GCStats struct {
+	NumGC         uint32    `json:"num_gc"`
+	NumForcedGC   uint32    `json:"num_forced_gc"`
+	LastGC        time.Time `json:"last_gc"`
+	NextGC        uint64    `json:"next_gc"` // 下一次 GC 的目标堆大小
+	PauseTotal    string    `json:"pause_total"`
+	RecentPauses  []string  `json:"recent_pauses"` // 最近的停顿时间，最新的在前
+	GCCPUFraction float64   `json:"gc_cpu_fraction"`
+	MemoryLimit   int64     `json:"memory_limit"` // GOMEMLIMIT，未设置时为 math.MaxInt64
+}
+
+// recentPauses RecentPauses 保留的停顿次数
+const recentPauses = 10
+
+// Runtime 读取当前运行时状态，ReadMemStats 会短暂暂停所有协程
+func Runtime() RuntimeStats {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	gc := debug.GCStats{Pause: make([]time.Duration, recentPauses)}
+	debug.ReadGCStats(&gc)
+	pauses := make([]string, 0, len(gc.Pause))
+	for _, pause := range gc.Pause {
+		pauses = append(pauses, pause.String())
+	}
+
+	return RuntimeStats{
+		GoVersion:    runtime.Version(),
+		StartedAt:    startedAt,
+		Uptime:       time.Since(startedAt).Round(time.Second).String(),
+		NumCPU:       runtime.NumCPU(),
+		GOMAXPROCS:   runtime.GOMAXPROCS(0),
+		NumGoroutine: runtime.NumGoroutine(),
+		NumCgoCall:   runtime.NumCgoCall(),
+		Memory: MemoryStats{
+			Alloc:        mem.Alloc,
+			TotalAlloc:   mem.TotalAlloc,
+			Sys:          mem.Sys,
+			HeapAlloc:    mem.HeapAlloc,
+			HeapSys:      mem.HeapSys,
+			HeapIdle:     mem.HeapIdle,
+			HeapInuse:    mem.HeapInuse,
+			HeapReleased: mem.HeapReleased,
+			HeapObjects:  mem.HeapObjects,
+			StackInuse:   mem.StackInuse,
+			Mallocs:      mem.Mallocs,
+			Frees:        mem.Frees,
+		},
+		GC: GCStats{
+			NumGC:         mem.NumGC,
+			NumForcedGC:   mem.NumForcedGC,
+			LastGC:        gc.LastGC,
+			NextGC:        mem.NextGC,
+			PauseTotal:    gc.PauseTotal.String(),
+			RecentPauses:  pauses,
+			GCCPUFraction: mem.GCCPUFraction,
+			MemoryLimit:   debug.SetMemoryLimit(-1),
+		},
+	}
+}
+
+// DBStats 数据库连接池状态
+type DBStats struct {
+	Name              string `json:"name"`
+	MaxOpen           int    `json:"max_open"`
+	Open              int    `json:"open"`
+	InUse             int    `json:"in_use"`
+	Idle              int    `json:"idle"`
+	WaitCount         int64  `json:"wait_count"`    // 累计等待连接的次数
+	WaitDuration      string `json:"wait_duration"` // 累计等待连接的时间
+	MaxIdleClosed     int64  `json:"max_idle_closed"`
+	MaxIdleTimeClosed int64  `json:"max_idle_time_closed"`
+	MaxLifetimeClosed int64  `json:"max_lifetime_closed"`
+}
+
+// Database 转换连接池状态，按名称排序
+func Database(pools map[string]sql.DBStats) []DBStats {
+	stats := make([]DBStats, 0, len(pools))
+	for name, s := range pools {
+		stats = append(stats, DBStats{
+			Name:              name,
+			MaxOpen:           s.MaxOpenConnections,
+			Open:              s.OpenConnections,
+			InUse:             s.InUse,
+			Idle:              s.Idle,
+			WaitCount:         s.WaitCount,
+			WaitDuration:      s.WaitDuration.String(),
+			MaxIdleClosed:     s.MaxIdleClosed,
+			MaxIdleTimeClosed: s.MaxIdleTimeClosed,
+			MaxLifetimeClosed: s.MaxLifetimeClosed,
+		})
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Name < stats[j].Name })
+	return stats
+}
+
+// Goroutines 以文本形式写出全部协程的调用栈，格式与 panic 时的输出相同
+func Goroutines(w io.Writer) error {
+	return pprof.Lookup("goroutine").WriteTo(w, 2)
+}
+
+// CheckLoopback 检查 addr 只监听本机地址，诊断接口单独监听时不做鉴权
+func CheckLoopback(addr string) error {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return err
+	}
+	if host == "localhost" {
+		return nil
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		return nil
+	}
+	return fmt.Errorf("debug address %q must listen on localhost or a loopback IP", addr)
+}
@@ -0,0 +1,50 @@
+package diagnostics
+
+import (
+	"database/sql"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRuntime(t *testing.T) {
+	runtime.GC()
+	stats := Runtime()
+	assert.Equal(t, runtime.Version(), stats.GoVersion)
+	assert.Positive(t, stats.NumGoroutine)
+	assert.Positive(t, stats.Memory.HeapAlloc)
+	assert.GreaterOrEqual(t, stats.GC.NumForcedGC, uint32(1))
+	assert.False(t, stats.GC.LastGC.IsZero())
+	assert.LessOrEqual(t, len(stats.GC.RecentPauses), recentPauses)
+}
+
+func TestDatabase(t *testing.T) {
+	stats := Database(map[string]sql.DBStats{
+		"replica:3306": {OpenConnections: 1},
+		"primary":      {MaxOpenConnections: 10, OpenConnections: 3, InUse: 2, Idle: 1, WaitDuration: 1500 * time.Millisecond},
+	})
+	require.Len(t, stats, 2)
+	assert.Equal(t, "primary", stats[0].Name)
+	assert.Equal(t, 2, stats[0].InUse)
+	assert.Equal(t, "1.5s", stats[0].WaitDuration)
+	assert.Equal(t, "replica:3306", stats[1].Name)
+}
+
+func TestGoroutines(t *testing.T) {
+	var b strings.Builder
+	require.NoError(t, Goroutines(&b))
+	assert.Contains(t, b.String(), "TestGoroutines")
+}
+
+func TestCheckLoopback(t *testing.T) {
+	for _, addr := range []string{"127.0.0.1:6060", "localhost:6060", "[::1]:6060"} {
+		assert.NoError(t, CheckLoopback(addr), addr)
+	}
+	for _, addr := range []string{":6060", "0.0.0.0:6060", "10.0.0.5:6060", "example.com:6060", "6060"} {
+		assert.Error(t, CheckLoopback(addr), addr)
+	}
+}
@@ -6,8 +6,10 @@ const (
 	AuthorizationError = 403
 	NotFound           = 404
 	NotLogin           = 401
+	Conflict           = 409
 	InvalidParameter   = 10000
 	UserDoesNotExist   = 10001
+	RecordExists       = 10002
 	ServerError        = 10101
 	TooManyRequests    = 10102
 )
@@ -8,6 +8,8 @@ var enUSText = map[int]string{
 	TooManyRequests:    "Too many requests",
 	InvalidParameter:   "Parameter error",
 	UserDoesNotExist:   "user does not exist",
+	RecordExists:       "record already exists",
 	AuthorizationError: "You have no permission",
 	NotLogin:           "Please login first",
+	Conflict:           "The record has been modified, please refresh and retry",
 }
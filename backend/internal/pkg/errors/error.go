@@ -9,6 +9,7 @@ import (
 type BusinessError struct {
 	code       int
 	message    string
+	data       any
 	contextErr []error
 }
 
@@ -32,6 +33,16 @@ func (e *BusinessError) SetMessage(message string) {
 	e.message = message
 }
 
+// WithData 附加随错误返回的数据，如版本冲突时服务端的当前数据
+func (e *BusinessError) WithData(data any) *BusinessError {
+	e.data = data
+	return e
+}
+
+func (e *BusinessError) GetData() any {
+	return e.data
+}
+
 func (e *BusinessError) SetContextErr(err error) {
 	e.contextErr = append(e.contextErr, err)
 }
@@ -8,6 +8,8 @@ var zhCNText = map[int]string{
 	TooManyRequests:    "请求过多",
 	InvalidParameter:   "参数错误",
 	UserDoesNotExist:   "用户不存在",
+	RecordExists:       "记录已存在",
 	AuthorizationError: "暂无访问权限",
 	NotLogin:           "请先登录",
+	Conflict:           "数据已被修改，请刷新后重试",
 }
@@ -0,0 +1,37 @@
+package gormhook
+
+import "gorm.io/gorm"
+
+// Hook 语句回调，operation 为 create、query、update、delete、row、raw 之一
+type Hook func(operation string, db *gorm.DB)
+
+type registrar interface {
+	Register(name string, fn func(*gorm.DB)) error
+}
+
+// Register 在每类语句的 GORM 内置处理前后注册 before、after 回调，
+// 回调名为 name:before_<operation>、name:after_<operation>
+func Register(db *gorm.DB, name string, before, after Hook) error {
+	cb := db.Callback()
+	steps := []struct {
+		operation     string
+		before, after registrar
+	}{
+		{"create", cb.Create().Before("gorm:create"), cb.Create().After("gorm:create")},
+		{"query", cb.Query().Before("gorm:query"), cb.Query().After("gorm:query")},
+		{"update", cb.Update().Before("gorm:update"), cb.Update().After("gorm:update")},
+		{"delete", cb.Delete().Before("gorm:delete"), cb.Delete().After("gorm:delete")},
+		{"row", cb.Row().Before("gorm:row"), cb.Row().After("gorm:row")},
+		{"raw", cb.Raw().Before("gorm:raw"), cb.Raw().After("gorm:raw")},
+	}
+	for _, step := range steps {
+		operation := step.operation
+		if err := step.before.Register(name+":before_"+operation, func(db *gorm.DB) { before(operation, db) }); err != nil {
+			return err
+		}
+		if err := step.after.Register(name+":after_"+operation, func(db *gorm.DB) { after(operation, db) }); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package gormhook
+
+import (
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type item struct {
+	ID   uint
+	Name string
+}
+
+func TestRegister(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Discard})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&item{}))
+
+	var calls []string
+	hook := func(side string) Hook {
+		return func(operation string, _ *gorm.DB) { calls = append(calls, side+"_"+operation) }
+	}
+	require.NoError(t, Register(db, "test", hook("before"), hook("after")))
+
+	record := item{Name: "a"}
+	require.NoError(t, db.Create(&record).Error)
+	require.NoError(t, db.First(&item{}).Error)
+	require.NoError(t, db.Model(&record).Update("name", "b").Error)
+	require.NoError(t, db.Delete(&record).Error)
+	var count int
+	require.NoError(t, db.Raw("SELECT COUNT(*) FROM items").Row().Scan(&count))
+	require.NoError(t, db.Exec("DELETE FROM items").Error)
+	assert.Equal(t, []string{
+		"before_create", "after_create",
+		"before_query", "after_query",
+		"before_update", "after_update",
+		"before_delete", "after_delete",
+		"before_row", "after_row",
+		"before_raw", "after_raw",
+	}, calls)
+
+}
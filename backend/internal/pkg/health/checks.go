@@ -0,0 +1,33 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var ErrNotReady = errors.New("not ready")
+
+// Ready 在 ready 返回 false 时检查失败，用于将服务启动与停止阶段反映到就绪探针
+func Ready(ready func() bool) CheckFunc {
+	return func(context.Context) error {
+		if !ready() {
+			return ErrNotReady
+		}
+		return nil
+	}
+}
+
+// DiskSpace 检查 path 所在磁盘的可用空间不少于 minFree 字节
+func DiskSpace(path string, minFree uint64) CheckFunc {
+	return func(context.Context) error {
+		free, err := freeSpace(path)
+		if err != nil {
+			return err
+		}
+		if free < minFree {
+			return fmt.Errorf("%s: %d MB free, want at least %d MB", path, free>>20, minFree>>20)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,186 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+)
+
+// CheckFunc 检查一个依赖是否可用，返回 nil 表示可用
+type CheckFunc func(ctx context.Context) error
+
+// Check 健康检查项
+type Check struct {
+	Name string
+	// Critical 为 true 时检查失败会使 /readyz 返回不可用，否则只在详细状态中体现
+	Critical bool
+	// Timeout 单次检查超时，<= 0 时使用注册表的默认值
+	Timeout time.Duration
+	Fn      CheckFunc
+}
+
+// Result 单个检查项的结果
+type Result struct {
+	Name        string     `json:"name"`
+	Status      string     `json:"status"`
+	Critical    bool       `json:"critical"`
+	LatencyMs   float64    `json:"latency_ms"`
+	Error       string     `json:"error,omitempty"`
+	CheckedAt   time.Time  `json:"checked_at"`
+	LastError   string     `json:"last_error,omitempty"`
+	LastErrorAt *time.Time `json:"last_error_at,omitempty"`
+}
+
+// Report 一次检查的汇总结果
+type Report struct {
+	Status string   `json:"status"`
+	Checks []Result `json:"checks"`
+}
+
+// Healthy 所有关键检查项均可用
+func (r Report) Healthy() bool {
+	return r.Status == StatusUp
+}
+
+type entry struct {
+	check       Check
+	lastError   string
+	lastErrorAt *time.Time
+}
+
+// Registry 健康检查注册表
+type Registry struct {
+	// Timeout 检查项未设置超时时使用的默认超时
+	Timeout time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*entry
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		Timeout: 2 * time.Second,
+		entries: make(map[string]*entry),
+	}
+}
+
+// Register 注册检查项，同名检查项会被替换
+func (r *Registry) Register(check Check) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries[check.Name] = &entry{check: check}
+}
+
+// Unregister 移除检查项
+func (r *Registry) Unregister(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.entries, name)
+}
+
+// Run 并发执行全部检查项，critical 为 true 时只执行关键检查项
+func (r *Registry) Run(ctx context.Context, critical bool) Report {
+	r.mu.Lock()
+	entries := make([]*entry, 0, len(r.entries))
+	for _, e := range r.entries {
+		if !critical || e.check.Critical {
+			entries = append(entries, e)
+		}
+	}
+	r.mu.Unlock()
+	sort.Slice(entries, func(i, j int) bool { return entries[i].check.Name < entries[j].check.Name })
+
+	results := make([]Result, len(entries))
+	var wg sync.WaitGroup
+	for i, e := range entries {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = r.run(ctx, e)
+		}()
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusUp, Checks: results}
+	for _, result := range results {
+		if result.Critical && result.Status != StatusUp {
+			report.Status = StatusDown
+		}
+	}
+	return report
+}
+
+func (r *Registry) run(ctx context.Context, e *entry) Result {
+	timeout := e.check.Timeout
+	if timeout <= 0 {
+		timeout = r.Timeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := safeCall(ctx, e.check.Fn)
+	result := Result{
+		Name:      e.check.Name,
+		Status:    StatusUp,
+		Critical:  e.check.Critical,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+		CheckedAt: start,
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+		e.lastError, e.lastErrorAt = result.Error, &start
+	}
+	result.LastError, result.LastErrorAt = e.lastError, e.lastErrorAt
+	return result
+}
+
+// safeCall 执行检查函数，检查函数不响应 ctx 时也按超时返回
+func safeCall(ctx context.Context, fn CheckFunc) error {
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if p := recover(); p != nil {
+				done <- &panicError{value: p}
+			}
+		}()
+		done <- fn(ctx)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+type panicError struct {
+	value any
+}
+
+func (e *panicError) Error() string {
+	return fmt.Sprintf("check panicked: %v", e.value)
+}
+
+var defaultRegistry = NewRegistry()
+
+// Default 返回全局注册表
+func Default() *Registry {
+	return defaultRegistry
+}
+
+// Register 向全局注册表注册检查项
+func Register(check Check) {
+	defaultRegistry.Register(check)
+}
@@ -0,0 +1,81 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func up(context.Context) error { return nil }
+
+func TestRunCriticalOnly(t *testing.T) {
+	r := NewRegistry()
+	r.Register(Check{Name: "db", Critical: true, Fn: up})
+	r.Register(Check{Name: "agent", Fn: func(context.Context) error { return errors.New("unavailable") }})
+
+	report := r.Run(context.Background(), true)
+	assert.True(t, report.Healthy())
+	require.Len(t, report.Checks, 1)
+	assert.Equal(t, "db", report.Checks[0].Name)
+
+	// 非关键检查失败不影响整体状态
+	report = r.Run(context.Background(), false)
+	assert.True(t, report.Healthy())
+	require.Len(t, report.Checks, 2)
+	assert.Equal(t, "agent", report.Checks[0].Name)
+	assert.Equal(t, StatusDown, report.Checks[0].Status)
+}
+
+func TestLastError(t *testing.T) {
+	r := NewRegistry()
+	fail := true
+	r.Register(Check{Name: "db", Critical: true, Fn: func(context.Context) error {
+		if fail {
+			return errors.New("connection refused")
+		}
+		return nil
+	}})
+
+	report := r.Run(context.Background(), true)
+	assert.False(t, report.Healthy())
+	assert.Equal(t, "connection refused", report.Checks[0].Error)
+
+	fail = false
+	report = r.Run(context.Background(), true)
+	assert.True(t, report.Healthy())
+	assert.Empty(t, report.Checks[0].Error)
+	assert.Equal(t, "connection refused", report.Checks[0].LastError)
+	assert.NotNil(t, report.Checks[0].LastErrorAt)
+}
+
+func TestTimeoutAndPanic(t *testing.T) {
+	r := NewRegistry()
+	r.Timeout = 20 * time.Millisecond
+	block := make(chan struct{})
+	defer close(block)
+	r.Register(Check{Name: "slow", Critical: true, Fn: func(context.Context) error {
+		<-block
+		return nil
+	}})
+	r.Register(Check{Name: "panic", Critical: true, Fn: func(context.Context) error { panic("boom") }})
+
+	report := r.Run(context.Background(), true)
+	assert.False(t, report.Healthy())
+	assert.Contains(t, report.Checks[0].Error, "boom")
+	assert.Equal(t, context.DeadlineExceeded.Error(), report.Checks[1].Error)
+}
+
+func TestReadyAndDiskSpace(t *testing.T) {
+	ready := false
+	check := Ready(func() bool { return ready })
+	assert.ErrorIs(t, check(context.Background()), ErrNotReady)
+	ready = true
+	assert.NoError(t, check(context.Background()))
+
+	assert.NoError(t, DiskSpace(t.TempDir(), 0)(context.Background()))
+	assert.Error(t, DiskSpace(t.TempDir(), 1<<62)(context.Background()))
+}
@@ -0,0 +1,145 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	log "insight/internal/pkg/logger"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Hook 生命周期钩子，按添加顺序启动，逆序停止
+type Hook struct {
+	Name    string
+	OnStart func(ctx context.Context) error
+	OnStop  func(ctx context.Context) error
+}
+
+// Lifecycle 管理服务组件的启动与优雅停止
+type Lifecycle struct {
+	// StopTimeout 停止所有钩子的最长时间，超时后未完成的钩子收到取消的上下文
+	StopTimeout time.Duration
+	// DrainDelay 收到退出信号后先将就绪状态置为 false，等待负载均衡摘除流量后再停止
+	DrainDelay time.Duration
+
+	mu      sync.Mutex
+	hooks   []Hook
+	started int
+	ready   atomic.Bool
+	errs    chan error
+}
+
+func New() *Lifecycle {
+	return &Lifecycle{
+		StopTimeout: 30 * time.Second,
+		errs:        make(chan error, 1),
+	}
+}
+
+// Append 添加钩子，先添加的先启动、后停止
+func (l *Lifecycle) Append(hook Hook) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.hooks = append(l.hooks, hook)
+}
+
+// Ready 所有钩子已启动且未开始停止
+func (l *Lifecycle) Ready() bool {
+	return l.ready.Load()
+}
+
+// Go 在后台运行 fn，fn 返回错误时触发停止
+func (l *Lifecycle) Go(name string, fn func() error) {
+	go func() {
+		if err := fn(); err != nil {
+			select {
+			case l.errs <- fmt.Errorf("%s: %w", name, err):
+			default:
+			}
+		}
+	}()
+}
+
+// Start 按顺序启动钩子，任一钩子失败时逆序停止已启动的钩子
+func (l *Lifecycle) Start(ctx context.Context) error {
+	l.mu.Lock()
+	hooks := l.hooks
+	l.mu.Unlock()
+
+	for i, hook := range hooks {
+		if hook.OnStart != nil {
+			if err := hook.OnStart(ctx); err != nil {
+				l.setStarted(i)
+				stopErr := l.Stop(context.WithoutCancel(ctx))
+				return errors.Join(fmt.Errorf("start %s: %w", hook.Name, err), stopErr)
+			}
+		}
+		log.Logger.Debug("Started", zap.String("hook", hook.Name))
+	}
+	l.setStarted(len(hooks))
+	l.ready.Store(true)
+	return nil
+}
+
+func (l *Lifecycle) setStarted(n int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.started = n
+}
+
+// Stop 逆序停止已启动的钩子，单个钩子失败不影响其余钩子
+func (l *Lifecycle) Stop(ctx context.Context) error {
+	l.ready.Store(false)
+	l.mu.Lock()
+	hooks := l.hooks[:l.started]
+	l.started = 0
+	l.mu.Unlock()
+
+	var errs []error
+	for i := len(hooks) - 1; i >= 0; i-- {
+		hook := hooks[i]
+		if hook.OnStop == nil {
+			continue
+		}
+		if err := hook.OnStop(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("stop %s: %w", hook.Name, err))
+			continue
+		}
+		log.Logger.Debug("Stopped", zap.String("hook", hook.Name))
+	}
+	return errors.Join(errs...)
+}
+
+// Run 启动所有钩子并阻塞，直到收到 SIGINT/SIGTERM、ctx 结束或后台任务出错，然后在 StopTimeout 内停止
+func (l *Lifecycle) Run(ctx context.Context) error {
+	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	if err := l.Start(ctx); err != nil {
+		return err
+	}
+
+	var runErr error
+	select {
+	case <-ctx.Done():
+		log.Logger.Info("Shutting down")
+	case runErr = <-l.errs:
+		log.Logger.Error("Shutting down after failure", zap.Error(runErr))
+	}
+
+	l.ready.Store(false)
+	if l.DrainDelay > 0 && runErr == nil {
+		time.Sleep(l.DrainDelay)
+	}
+
+	stopCtx, stopCancel := context.WithTimeout(context.Background(), l.StopTimeout)
+	defer stopCancel()
+	return errors.Join(runErr, l.Stop(stopCtx))
+}
@@ -0,0 +1,96 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	log "insight/internal/pkg/logger"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func init() {
+	log.Logger = zap.NewNop()
+}
+
+func recorder(events *[]string, name string, startErr error) Hook {
+	return Hook{
+		Name: name,
+		OnStart: func(context.Context) error {
+			*events = append(*events, "start "+name)
+			return startErr
+		},
+		OnStop: func(context.Context) error {
+			*events = append(*events, "stop "+name)
+			return nil
+		},
+	}
+}
+
+func TestStartStopOrder(t *testing.T) {
+	var events []string
+	l := New()
+	l.Append(recorder(&events, "db", nil))
+	l.Append(recorder(&events, "http", nil))
+
+	require.NoError(t, l.Start(context.Background()))
+	assert.True(t, l.Ready())
+	require.NoError(t, l.Stop(context.Background()))
+	assert.False(t, l.Ready())
+	assert.Equal(t, []string{"start db", "start http", "stop http", "stop db"}, events)
+}
+
+func TestStartFailureRollsBack(t *testing.T) {
+	var events []string
+	l := New()
+	l.Append(recorder(&events, "db", nil))
+	l.Append(recorder(&events, "http", errors.New("address in use")))
+	l.Append(recorder(&events, "cron", nil))
+
+	err := l.Start(context.Background())
+	assert.ErrorContains(t, err, "start http: address in use")
+	assert.False(t, l.Ready())
+	assert.Equal(t, []string{"start db", "start http", "stop db"}, events, "Only started hooks should be stopped")
+}
+
+func TestRunStopsOnContextAndFailure(t *testing.T) {
+	var events []string
+	l := New()
+	l.Append(recorder(&events, "db", nil))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- l.Run(ctx) }()
+	require.Eventually(t, l.Ready, time.Second, time.Millisecond)
+	cancel()
+	require.NoError(t, <-done)
+	assert.Equal(t, []string{"start db", "stop db"}, events)
+
+	events = nil
+	l = New()
+	l.Append(recorder(&events, "http", nil))
+	l.Append(Hook{Name: "serve", OnStart: func(context.Context) error {
+		l.Go("serve", func() error { return errors.New("listener closed") })
+		return nil
+	}})
+	err := l.Run(context.Background())
+	assert.ErrorContains(t, err, "serve: listener closed")
+	assert.Equal(t, []string{"start http", "stop http"}, events)
+}
+
+func TestStopTimeout(t *testing.T) {
+	l := New()
+	l.StopTimeout = 10 * time.Millisecond
+	l.Append(Hook{Name: "slow", OnStop: func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := l.Run(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -0,0 +1,56 @@
+package logger
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+type ctxKey struct{}
+
+// NewContext 将请求级日志对象存入 ctx
+func NewContext(ctx context.Context, logger *zap.Logger) context.Context {
+	return context.WithValue(ctx, ctxKey{}, logger)
+}
+
+// FromContext 返回 ctx 中的请求级日志对象，不存在时返回全局 Logger
+func FromContext(ctx context.Context) *zap.Logger {
+	if logger, ok := ctx.Value(ctxKey{}).(*zap.Logger); ok {
+		return logger
+	}
+	return Logger
+}
+
+// WithFields 为 ctx 中的日志对象追加字段，如认证后追加 user_id
+func WithFields(ctx context.Context, fields ...zap.Field) context.Context {
+	return NewContext(ctx, FromContext(ctx).With(fields...))
+}
+
+// WithContext 返回请求级日志对象，并附带 ctx 中的 trace_id、span_id
+func WithContext(ctx context.Context) *zap.Logger {
+	return withTrace(ctx, FromContext(ctx))
+}
+
+// WithContextOr 与 WithContext 相同，但 ctx 中没有请求级日志对象时使用 fallback，fallback 为 nil 时使用全局 Logger
+func WithContextOr(ctx context.Context, fallback *zap.Logger) *zap.Logger {
+	logger, ok := ctx.Value(ctxKey{}).(*zap.Logger)
+	if !ok {
+		logger = fallback
+	}
+	if logger == nil {
+		logger = Logger
+	}
+	return withTrace(ctx, logger)
+}
+
+func withTrace(ctx context.Context, logger *zap.Logger) *zap.Logger {
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.IsValid() {
+		return logger
+	}
+	return logger.With(
+		zap.String("trace_id", spanContext.TraceID().String()),
+		zap.String("span_id", spanContext.SpanID().String()),
+	)
+}
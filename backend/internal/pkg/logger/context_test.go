@@ -0,0 +1,57 @@
+package logger
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// 测试请求级日志对象与链路字段
+func TestWithContext(t *testing.T) {
+	testLogger, logs := createInMemoryLogCore()
+	origLogger := Logger
+	defer func() { Logger = origLogger }()
+	Logger = testLogger
+
+	// 没有请求级日志对象时使用全局 Logger
+	WithContext(context.Background()).Info("global")
+	assert.Empty(t, logs.All()[0].Context)
+
+	ctx := NewContext(context.Background(), Logger.With(zap.String("request_id", "req-1")))
+	ctx = WithFields(ctx, zap.Uint("user_id", 7))
+	spanContext := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{1},
+		SpanID:  trace.SpanID{2},
+	})
+	ctx = trace.ContextWithSpanContext(ctx, spanContext)
+
+	WithContext(ctx).Info("request")
+	fields := logs.All()[1].ContextMap()
+	assert.Equal(t, "req-1", fields["request_id"])
+	assert.Equal(t, uint64(7), fields["user_id"])
+	assert.Equal(t, spanContext.TraceID().String(), fields["trace_id"])
+	assert.Equal(t, spanContext.SpanID().String(), fields["span_id"])
+}
+
+// 测试没有请求级日志对象时使用注入的日志对象
+func TestWithContextOr(t *testing.T) {
+	globalLogger, globalLogs := createInMemoryLogCore()
+	origLogger := Logger
+	defer func() { Logger = origLogger }()
+	Logger = globalLogger
+	injected, injectedLogs := createInMemoryLogCore()
+
+	WithContextOr(context.Background(), injected).Info("injected")
+	assert.Equal(t, 1, injectedLogs.Len())
+
+	WithContextOr(context.Background(), nil).Info("global")
+	assert.Equal(t, 1, globalLogs.Len())
+
+	ctx := NewContext(context.Background(), Logger.With(zap.String("request_id", "req-1")))
+	WithContextOr(ctx, injected).Info("request")
+	assert.Equal(t, 2, globalLogs.Len(), "Request logger should take precedence")
+	assert.Equal(t, 1, injectedLogs.Len())
+}
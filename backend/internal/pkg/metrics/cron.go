@@ -0,0 +1,45 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	cronRuns = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "cron",
+		Name:      "job_runs_total",
+		Help:      "Scheduled job runs by job and result.",
+	}, []string{"job", "status"})
+
+	cronDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "cron",
+		Name:      "job_duration_seconds",
+		Help:      "Scheduled job duration.",
+		Buckets:   []float64{.01, .1, .5, 1, 5, 10, 30, 60, 300, 900},
+	}, []string{"job"})
+)
+
+func init() {
+	registry.MustRegister(cronRuns, cronDuration)
+}
+
+// ObserveJob 执行任务并按 success、error、panic 记录次数与耗时，panic 会继续向上抛出由 cron.Recover 处理
+func ObserveJob(name string, job func() error) func() {
+	return func() {
+		start := time.Now()
+		status := "panic"
+		defer func() {
+			cronRuns.WithLabelValues(name, status).Inc()
+			cronDuration.WithLabelValues(name).Observe(time.Since(start).Seconds())
+		}()
+		if err := job(); err != nil {
+			status = "error"
+			return
+		}
+		status = "success"
+	}
+}
@@ -0,0 +1,52 @@
+package metrics
+
+import (
+	"errors"
+	"insight/internal/pkg/gormhook"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"gorm.io/gorm"
+)
+
+const gormStartKey = "metrics:start_time"
+
+var dbDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: namespace,
+	Subsystem: "db",
+	Name:      "query_duration_seconds",
+	Help:      "GORM statement latency by operation, table and result.",
+	Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+}, []string{"operation", "table", "status"})
+
+func init() {
+	registry.MustRegister(dbDuration)
+}
+
+// GormPlugin 通过回调记录每条语句的耗时
+type GormPlugin struct{}
+
+func (GormPlugin) Name() string {
+	return "metrics"
+}
+
+func (p GormPlugin) Initialize(db *gorm.DB) error {
+	return gormhook.Register(db, "metrics", before, after)
+}
+
+func before(_ string, db *gorm.DB) {
+	db.InstanceSet(gormStartKey, time.Now())
+}
+
+func after(operation string, db *gorm.DB) {
+	value, ok := db.InstanceGet(gormStartKey)
+	if !ok {
+		return
+	}
+	status := "ok"
+	if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+		status = "error"
+	}
+	dbDuration.WithLabelValues(operation, db.Statement.Table, status).
+		Observe(time.Since(value.(time.Time)).Seconds())
+}
@@ -0,0 +1,42 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	grpcRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "grpc_client",
+		Name:      "requests_total",
+		Help:      "gRPC client calls by method and status code.",
+	}, []string{"method", "code"})
+
+	grpcDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "grpc_client",
+		Name:      "request_duration_seconds",
+		Help:      "gRPC client call latency by method.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method"})
+)
+
+func init() {
+	registry.MustRegister(grpcRequests, grpcDuration)
+}
+
+// UnaryClientInterceptor 记录 gRPC 客户端调用次数与耗时
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		grpcRequests.WithLabelValues(method, status.Code(err).String()).Inc()
+		grpcDuration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+		return err
+	}
+}
@@ -0,0 +1,39 @@
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "HTTP requests by route template, status and business code.",
+	}, []string{"method", "route", "status", "code"})
+
+	httpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "HTTP request latency by route template.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+)
+
+func init() {
+	registry.MustRegister(httpRequests, httpDuration)
+}
+
+// ObserveHTTP 记录一次 HTTP 请求，route 为路由模板，code 为响应体中的业务码，非统一响应格式时传空字符串
+func ObserveHTTP(method, route string, status int, code string, duration time.Duration) {
+	if route == "" {
+		// 未匹配路由的请求统一归类，避免任意路径撑爆标签基数
+		route = "unmatched"
+	}
+	httpRequests.WithLabelValues(method, route, strconv.Itoa(status), code).Inc()
+	httpDuration.WithLabelValues(method, route).Observe(duration.Seconds())
+}
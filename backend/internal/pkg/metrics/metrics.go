@@ -0,0 +1,36 @@
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "insight"
+
+var registry = prometheus.NewRegistry()
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+// Registry 返回应用使用的指标注册表
+func Registry() *prometheus.Registry {
+	return registry
+}
+
+// Handler 以 Prometheus 文本格式输出全部指标
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{Registry: registry})
+}
+
+// RegisterDBStats 注册连接池统计，name 用于区分主库与从库
+func RegisterDBStats(db *sql.DB, name string) error {
+	return registry.Register(collectors.NewDBStatsCollector(db, name))
+}
@@ -0,0 +1,80 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestObserveHTTP(t *testing.T) {
+	ObserveHTTP("GET", "/api/users/:id", 200, "0", 10*time.Millisecond)
+	ObserveHTTP("GET", "", 404, "", time.Millisecond)
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(httpRequests.WithLabelValues("GET", "/api/users/:id", "200", "0")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(httpRequests.WithLabelValues("GET", "unmatched", "404", "")))
+}
+
+func TestGormPlugin(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Discard})
+	require.NoError(t, err)
+	require.NoError(t, db.Use(GormPlugin{}))
+
+	type item struct {
+		ID   uint
+		Name string
+	}
+	require.NoError(t, db.AutoMigrate(&item{}))
+	require.NoError(t, db.Create(&item{Name: "a"}).Error)
+	assert.ErrorIs(t, db.First(&item{}, 100).Error, gorm.ErrRecordNotFound)
+
+	assert.Equal(t, uint64(1), sampleCount(t, dbDuration.WithLabelValues("create", "items", "ok")))
+	// 记录不存在不计为错误
+	assert.Equal(t, uint64(1), sampleCount(t, dbDuration.WithLabelValues("query", "items", "ok")))
+}
+
+func sampleCount(t *testing.T, observer prometheus.Observer) uint64 {
+	var m dto.Metric
+	require.NoError(t, observer.(prometheus.Metric).Write(&m))
+	return m.GetHistogram().GetSampleCount()
+}
+
+func TestUnaryClientInterceptor(t *testing.T) {
+	interceptor := UnaryClientInterceptor()
+	invoker := func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error {
+		return status.Error(codes.Unavailable, "down")
+	}
+	err := interceptor(context.Background(), "/hello.Greeter/SayHello", nil, nil, nil, invoker)
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.Equal(t, 1.0, testutil.ToFloat64(grpcRequests.WithLabelValues("/hello.Greeter/SayHello", "Unavailable")))
+}
+
+func TestObserveJob(t *testing.T) {
+	ObserveJob("ok", func() error { return nil })()
+	ObserveJob("fail", func() error { return errors.New("boom") })()
+	assert.Panics(t, ObserveJob("panic", func() error { panic("boom") }))
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(cronRuns.WithLabelValues("ok", "success")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(cronRuns.WithLabelValues("fail", "error")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(cronRuns.WithLabelValues("panic", "panic")))
+}
+
+func TestHandler(t *testing.T) {
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Equal(t, 200, rec.Code)
+	assert.Contains(t, rec.Body.String(), "go_goroutines")
+}
@@ -0,0 +1,228 @@
+package openapi
+
+import (
+	"insight/internal/pkg/deprecation"
+	"reflect"
+	"regexp"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// resultSchema 统一响应结构在 components 中的名称
+const resultSchema = "Result"
+
+// versionRegexp 路径中的 API 版本号
+var versionRegexp = regexp.MustCompile(`/v\d+\b`)
+
+// Operation 接口文档，Query、Body、Response 传入对应类型的零值
+type Operation struct {
+	Summary     string
+	Description string
+	Tags        []string // 默认为控制器名称
+	Path        any      // 路径参数，按 uri 标签确定类型，未提供时为字符串
+	Query       any      // 查询参数，按 form 标签生成
+	Body        any      // JSON 请求体，按 json 标签生成
+	Response    any      // 响应中 data 的类型
+	Raw         bool     // 响应不使用统一的 Result 结构
+	Auth        bool     // 需要携带 Authorization 请求头
+	Hidden      bool     // 不在文档中显示
+}
+
+var (
+	mu         sync.RWMutex
+	operations = map[string]Operation{}
+)
+
+// Describe 为控制器方法添加接口文档，handler 可以是方法值或方法表达式，
+// 同一方法注册到多个路由时共用文档
+func Describe(handler any, op Operation) {
+	mu.Lock()
+	defer mu.Unlock()
+	operations[handlerName(handler)] = op
+}
+
+func lookup(name string) Operation {
+	mu.RLock()
+	defer mu.RUnlock()
+	return operations[name]
+}
+
+func handlerName(handler any) string {
+	return normalizeName(runtime.FuncForPC(reflect.ValueOf(handler).Pointer()).Name())
+}
+
+// normalizeName 统一方法值(-fm)、方法表达式以及指针与值接收者的函数名，与 gin.RouteInfo.Handler 对应
+func normalizeName(name string) string {
+	name = strings.TrimSuffix(name, "-fm")
+	return strings.NewReplacer("(*", "", ")", "").Replace(name)
+}
+
+// Build 由已注册的 gin 路由生成文档，未调用 Describe 的路由只包含路径参数
+func Build(routes gin.RoutesInfo, info Info) *Document {
+	g := newGenerator()
+	doc := &Document{
+		OpenAPI: "3.0.3",
+		Info:    info,
+		Paths:   map[string]PathItem{},
+		Components: Components{
+			Schemas: g.schemas,
+			SecuritySchemes: map[string]*SecurityScheme{
+				"bearerAuth": {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+			},
+		},
+	}
+
+	routes = slices.Clone(routes)
+	slices.SortFunc(routes, func(a, b gin.RouteInfo) int {
+		if c := strings.Compare(a.Path, b.Path); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Method, b.Method)
+	})
+	ids := map[string]int{}
+	for _, route := range routes {
+		name := normalizeName(route.Handler)
+		op := lookup(name)
+		if op.Hidden {
+			continue
+		}
+		path, pathParams := convertPath(route.Path)
+		tag, id := names(name, route.Method, path)
+		if ids[id]++; ids[id] > 1 {
+			id += "_" + strconv.Itoa(ids[id])
+		}
+		operation := &OperationObject{
+			OperationID: id,
+			Summary:     op.Summary,
+			Description: op.Description,
+			Tags:        op.Tags,
+			Responses:   map[string]*Response{"200": g.response(op)},
+		}
+		if len(operation.Tags) == 0 && tag != "" {
+			operation.Tags = []string{tag}
+		}
+		for _, param := range pathParams {
+			operation.Parameters = append(operation.Parameters, g.pathParameter(param, op))
+		}
+		operation.Parameters = append(operation.Parameters, g.parameters(op.Query)...)
+		if op.Body != nil {
+			operation.RequestBody = &RequestBody{
+				Required: true,
+				Content:  map[string]MediaType{"application/json": {Schema: g.schema(reflect.TypeOf(op.Body))}},
+			}
+		}
+		if op.Auth {
+			operation.Security = []map[string][]string{{"bearerAuth": {}}}
+		}
+		if policy, ok := deprecation.Lookup(route.Method, route.Path); ok {
+			operation.Deprecated = true
+			operation.Description = strings.TrimSpace(operation.Description + "\n\n" + deprecationNote(policy))
+		}
+
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = PathItem{}
+		}
+		doc.Paths[path][strings.ToLower(route.Method)] = operation
+	}
+	return doc
+}
+
+// response 成功响应，data 的类型由 Operation.Response 决定
+func (g *generator) response(op Operation) *Response {
+	var s *Schema
+	switch {
+	case op.Raw && op.Response == nil:
+		return &Response{Description: "OK"}
+	case op.Raw:
+		s = g.schema(reflect.TypeOf(op.Response))
+	case op.Response == nil:
+		s = ref(resultSchema)
+	default:
+		s = &Schema{AllOf: []*Schema{
+			ref(resultSchema),
+			{Type: "object", Properties: map[string]*Schema{"data": g.schema(reflect.TypeOf(op.Response))}},
+		}}
+	}
+	return &Response{Description: "OK", Content: map[string]MediaType{"application/json": {Schema: s}}}
+}
+
+// pathParameter 路径参数，类型取自 Operation.Path 中 uri 标签同名的字段，没有时为字符串
+func (g *generator) pathParameter(name string, op Operation) *Parameter {
+	param := &Parameter{Name: name, In: "path", Required: true, Schema: &Schema{Type: "string"}}
+	if op.Path == nil {
+		return param
+	}
+	for _, f := range fields(deref(reflect.TypeOf(op.Path)), "uri") {
+		if f.name == name {
+			param = g.parameter(f, "path")
+			param.Required = true
+			break
+		}
+	}
+	return param
+}
+
+// envelope 统一响应结构 response.Result
+func envelope() *Schema {
+	return &Schema{
+		Type:     "object",
+		Required: []string{"code", "msg", "data", "cost"},
+		Properties: map[string]*Schema{
+			"code":       {Type: "integer", Description: "业务码，0 表示成功"},
+			"msg":        {Type: "string"},
+			"data":       {Nullable: true},
+			"cost":       {Type: "string", Description: "处理耗时"},
+			"request_id": {Type: "string"},
+		},
+	}
+}
+
+// convertPath 将 gin 路径中的 :id、*path 转换为 {id}、{path}，返回路径参数
+func convertPath(ginPath string) (string, []string) {
+	segments := strings.Split(ginPath, "/")
+	var params []string
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			params = append(params, segment[1:])
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/"), params
+}
+
+// deprecationNote 弃用说明
+func deprecationNote(policy deprecation.Policy) string {
+	note := "已于 " + policy.Since.Format(deprecation.DayLayout) + " 弃用"
+	if !policy.Sunset.IsZero() {
+		note += "，计划于 " + policy.Sunset.Format(deprecation.DayLayout) + " 下线"
+	}
+	if policy.Successor != "" {
+		note += "，请使用 " + policy.Successor
+	}
+	return note + "。"
+}
+
+// names 由处理函数名生成标签与 operationId，控制器方法 admin.AdminUserController.List
+// 对应标签 AdminUser 与 AdminUser_List，路径中带有版本号时加上版本前缀，如 v2_AdminUser_List；
+// 其他处理函数按请求方法与路径生成 operationId
+func names(handler, method, path string) (tag, id string) {
+	parts := strings.Split(handler[strings.LastIndex(handler, "/")+1:], ".")
+	if len(parts) == 3 && strings.HasSuffix(parts[1], "Controller") {
+		tag = strings.TrimSuffix(parts[1], "Controller")
+		id = tag + "_" + parts[2]
+		if version := versionRegexp.FindString(path); version != "" {
+			id = version[1:] + "_" + id
+		}
+		return tag, id
+	}
+	id = strings.ToLower(method)
+	for _, segment := range strings.FieldsFunc(path, func(r rune) bool { return r == '/' || r == '{' || r == '}' || r == '.' }) {
+		id += "_" + segment
+	}
+	return "", id
+}
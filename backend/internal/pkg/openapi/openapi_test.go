@@ -0,0 +1,146 @@
+package openapi
+
+import (
+	"insight/internal/pkg/deprecation"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type listQuery struct {
+	Page   int      `form:"page" binding:"omitempty,gt=0"`
+	Filter []string `form:"filter" binding:"omitempty,max=20"`
+	Status *int8    `form:"status" binding:"omitempty,oneof=0 1" label:"状态"`
+}
+
+type editForm struct {
+	ID     uint   `json:"id" binding:"required"`
+	Name   string `json:"name" binding:"required,min=5,max=60"`
+	Mobile string `json:"mobile" binding:"omitempty,mobile"`
+	Email  string `json:"email,omitempty" binding:"omitempty,email"`
+	Ignore string `json:"-"`
+}
+
+type idPath struct {
+	ID uint `uri:"id" binding:"required" label:"编号"`
+}
+
+type Result struct {
+	Name      string    `json:"name"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+type page[T any] struct {
+	Total int64 `json:"total"`
+	Data  []T
+}
+
+type testController struct{}
+
+func (testController) List(*gin.Context)    {}
+func (*testController) Edit(*gin.Context)   {}
+func (*testController) Detail(*gin.Context) {}
+func (testController) Hidden(*gin.Context)  {}
+
+func TestHandlerName(t *testing.T) {
+	var c testController
+	// 方法值与方法表达式、指针与值接收者的名称一致
+	assert.Equal(t, handlerName(testController.List), handlerName(c.List))
+	assert.Equal(t, handlerName((*testController).Edit), handlerName(c.Edit))
+	assert.True(t, strings.HasSuffix(handlerName(c.Edit), "openapi.testController.Edit"))
+}
+
+func TestBuild(t *testing.T) {
+	var c testController
+	Describe(testController.List, Operation{Summary: "列表", Query: listQuery{}, Response: page[*Result]{}, Auth: true})
+	Describe((*testController).Edit, Operation{Body: editForm{}})
+	Describe((*testController).Detail, Operation{Path: idPath{}, Response: ""})
+	Describe(testController.Hidden, Operation{Hidden: true})
+
+	r := gin.New()
+	r.GET("/api/items", c.List)
+	r.POST("/api/items", c.Edit)
+	r.GET("/api/items/:id", c.Detail)
+	r.GET("/api/v2/items", c.List)
+	r.GET("/api/legacy/items", c.List)
+	deprecation.Register(http.MethodGet, "/api/legacy/items", deprecation.Policy{
+		Since:     time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		Successor: "/api/v2/items",
+	})
+	r.GET("/api/docs", c.Hidden)
+	r.GET("/ping", func(*gin.Context) {})
+	doc := Build(r.Routes(), Info{Title: "Test", Version: "1.0.0"})
+
+	assert.Equal(t, "3.0.3", doc.OpenAPI)
+	assert.NotContains(t, doc.Paths, "/api/docs")
+	require.Contains(t, doc.Paths, "/api/items/{id}")
+
+	list := doc.Paths["/api/items"]["get"]
+	require.NotNil(t, list)
+	assert.Equal(t, "test_List", list.OperationID)
+	assert.Equal(t, []string{"test"}, list.Tags)
+	assert.Equal(t, []map[string][]string{{"bearerAuth": {}}}, list.Security)
+	require.Len(t, list.Parameters, 3)
+	assert.Equal(t, "page", list.Parameters[0].Name)
+	assert.True(t, list.Parameters[0].Schema.ExclusiveMinimum)
+	assert.Equal(t, 20, *list.Parameters[1].Schema.MaxItems)
+	assert.Equal(t, []any{int64(0), int64(1)}, list.Parameters[2].Schema.Enum)
+	assert.Equal(t, "状态", list.Parameters[2].Description)
+	assert.False(t, list.Deprecated)
+	assert.Equal(t, "v2_test_List", doc.Paths["/api/v2/items"]["get"].OperationID)
+	legacy := doc.Paths["/api/legacy/items"]["get"]
+	assert.Equal(t, "test_List_2", legacy.OperationID, "Operation IDs should be unique")
+	assert.True(t, legacy.Deprecated)
+	assert.Contains(t, legacy.Description, "/api/v2/items")
+
+	// 统一响应结构与测试中的 Result 重名
+	data := list.Responses["200"].Content["application/json"].Schema.AllOf[1].Properties["data"]
+	assert.Equal(t, "#/components/schemas/pageResult", data.Ref)
+	assert.Equal(t, "#/components/schemas/OpenapiResult", doc.Components.Schemas["pageResult"].Properties["Data"].Items.Ref)
+	assert.Equal(t, "date-time", doc.Components.Schemas["OpenapiResult"].Properties["checked_at"].Format)
+	assert.Contains(t, doc.Components.Schemas["Result"].Properties, "code")
+
+	edit := doc.Paths["/api/items"]["post"]
+	assert.Equal(t, "#/components/schemas/editForm", edit.RequestBody.Content["application/json"].Schema.Ref)
+	assert.Equal(t, "#/components/schemas/Result", edit.Responses["200"].Content["application/json"].Schema.Ref)
+	form := doc.Components.Schemas["editForm"]
+	assert.Equal(t, []string{"id", "name"}, form.Required)
+	assert.Equal(t, 5, *form.Properties["name"].MinLength)
+	assert.Equal(t, 60, *form.Properties["name"].MaxLength)
+	assert.Equal(t, rulePatterns["mobile"], form.Properties["mobile"].Pattern)
+	assert.Equal(t, "email", form.Properties["email"].Format)
+	assert.NotContains(t, form.Properties, "Ignore")
+
+	detail := doc.Paths["/api/items/{id}"]["get"]
+	require.Len(t, detail.Parameters, 1)
+	assert.Equal(t, &Parameter{Name: "id", In: "path", Description: "编号", Required: true, Schema: &Schema{Type: "integer", Format: "int64", Minimum: float(0)}}, detail.Parameters[0])
+
+	ping := doc.Paths["/ping"]["get"]
+	assert.Equal(t, "get_ping", ping.OperationID)
+	assert.Empty(t, ping.Tags)
+}
+
+func TestConvertPath(t *testing.T) {
+	path, params := convertPath("/api/trash/:resource/files/*path")
+	assert.Equal(t, "/api/trash/{resource}/files/{path}", path)
+	assert.Equal(t, []string{"resource", "path"}, params)
+}
+
+func TestUIHandler(t *testing.T) {
+	handler := UIHandler("Test", "../openapi.json")
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `url: "../openapi.json"`)
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/swagger-ui-bundle.js", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
@@ -0,0 +1,316 @@
+package openapi
+
+import (
+	"encoding/json"
+	"insight/internal/pkg/utils"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	formatDateType = reflect.TypeOf(utils.FormatDate{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// rulePatterns 自定义校验规则对应的正则，需与 validator.registerValidation 保持一致
+var rulePatterns = map[string]string{
+	"mobile": `^1[3456789]\d{9}$`,
+}
+
+// ruleFormats 校验规则对应的 format
+var ruleFormats = map[string]string{
+	"email": "email",
+	"url":   "uri",
+	"uuid":  "uuid",
+	"ipv4":  "ipv4",
+	"ipv6":  "ipv6",
+}
+
+// generator 通过反射生成 Schema，具名结构体放入 components.schemas
+type generator struct {
+	schemas map[string]*Schema
+	names   map[reflect.Type]string
+	used    map[string]reflect.Type
+}
+
+func newGenerator() *generator {
+	return &generator{
+		schemas: map[string]*Schema{resultSchema: envelope()},
+		names:   map[reflect.Type]string{},
+		// 统一响应结构占用 Result，同名的类型加上包名
+		used: map[string]reflect.Type{resultSchema: nil},
+	}
+}
+
+func ref(name string) *Schema {
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+// schema 返回类型对应的 Schema
+func (g *generator) schema(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case formatDateType:
+		return &Schema{Type: "string", Description: "2006-01-02 15:04:05"}
+	case rawMessageType:
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int, reflect.Int64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32", Minimum: float(0)}
+	case reflect.Uint, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64", Minimum: float(0)}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: g.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.object(t)
+		}
+		name := g.name(t)
+		if _, ok := g.schemas[name]; !ok {
+			// 先占位，避免自引用的类型无限递归
+			g.schemas[name] = &Schema{}
+			*g.schemas[name] = *g.object(t)
+		}
+		return ref(name)
+	default:
+		// interface 等任意类型
+		return &Schema{}
+	}
+}
+
+// object 按 json 标签生成对象的属性
+func (g *generator) object(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	for _, f := range fields(t, "json") {
+		property, required := g.field(f)
+		s.Properties[f.name] = property
+		if required {
+			s.Required = append(s.Required, f.name)
+		}
+	}
+	return s
+}
+
+// field 返回字段的 Schema，binding 规则转换为约束，label 作为描述
+func (g *generator) field(f field) (*Schema, bool) {
+	s := g.schema(f.typ)
+	required := applyRules(s, f.typ, f.binding)
+	if f.label != "" {
+		if s.Ref != "" {
+			// $ref 的同级字段会被忽略
+			s = &Schema{AllOf: []*Schema{s}}
+		}
+		s.Description = f.label
+	}
+	return s, required
+}
+
+// parameters 按 form 标签将结构体字段转换为查询参数
+func (g *generator) parameters(v any) []*Parameter {
+	if v == nil {
+		return nil
+	}
+	var params []*Parameter
+	for _, f := range fields(deref(reflect.TypeOf(v)), "form") {
+		params = append(params, g.parameter(f, "query"))
+	}
+	return params
+}
+
+// parameter 字段对应的参数，label 作为参数的描述
+func (g *generator) parameter(f field, in string) *Parameter {
+	s := g.schema(f.typ)
+	required := applyRules(s, f.typ, f.binding)
+	return &Parameter{Name: f.name, In: in, Description: f.label, Required: required, Schema: s}
+}
+
+// name 返回具名类型在 components 中的名称，不同包的同名类型加上包名区分
+func (g *generator) name(t reflect.Type) string {
+	if name, ok := g.names[t]; ok {
+		return name
+	}
+	name := typeName(t)
+	if other, ok := g.used[name]; ok && other != t {
+		pkg := path.Base(t.PkgPath())
+		name = strings.ToUpper(pkg[:1]) + pkg[1:] + name
+	}
+	g.names[t] = name
+	g.used[name] = t
+	return name
+}
+
+// typeName 去掉泛型参数中的包路径，如 Collection[*insight/internal/resources.RoleResources] 转为 CollectionRoleResources
+func typeName(t reflect.Type) string {
+	base, args, generic := strings.Cut(t.Name(), "[")
+	if !generic {
+		return base
+	}
+	var b strings.Builder
+	b.WriteString(base)
+	for _, arg := range strings.Split(strings.TrimSuffix(args, "]"), ",") {
+		arg = strings.TrimLeft(arg, "*[]")
+		b.WriteString(arg[strings.LastIndex(arg, ".")+1:])
+	}
+	return b.String()
+}
+
+type field struct {
+	name    string
+	typ     reflect.Type
+	binding string
+	label   string
+}
+
+// fields 返回结构体按 tagKey 标签命名的字段，未命名的嵌入结构体会被展开
+func fields(t reflect.Type, tagKey string) []field {
+	var result []field
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get(tagKey), ",")
+		if name == "-" {
+			continue
+		}
+		if f.Anonymous && name == "" && deref(f.Type).Kind() == reflect.Struct {
+			result = append(result, fields(deref(f.Type), tagKey)...)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		result = append(result, field{name: name, typ: f.Type, binding: f.Tag.Get("binding"), label: f.Tag.Get("label")})
+	}
+	return result
+}
+
+// applyRules 将 binding 标签中的校验规则转换为 Schema 约束，返回是否必填
+func applyRules(s *Schema, t reflect.Type, binding string) (required bool) {
+	kind := deref(t).Kind()
+	for _, rule := range strings.Split(binding, ",") {
+		tag, param, _ := strings.Cut(rule, "=")
+		// dive 之后的规则作用于元素
+		if tag == "dive" {
+			break
+		}
+		if tag == "required" {
+			required = true
+			continue
+		}
+		// 引用的 Schema 不能附加约束
+		if s.Ref != "" {
+			continue
+		}
+		switch tag {
+		case "min", "max", "len", "gt", "gte", "lt", "lte":
+			if n, err := strconv.ParseFloat(param, 64); err == nil {
+				applyBound(s, kind, tag, n)
+			}
+		case "oneof":
+			for _, value := range strings.Fields(param) {
+				s.Enum = append(s.Enum, enumValue(s.Type, value))
+			}
+		default:
+			if pattern, ok := rulePatterns[tag]; ok {
+				s.Pattern = pattern
+			} else if format, ok := ruleFormats[tag]; ok {
+				s.Format = format
+			}
+		}
+	}
+	return required
+}
+
+// applyBound 与 validator 一致，字符串限制长度，切片限制元素个数，数字限制取值
+func applyBound(s *Schema, kind reflect.Kind, tag string, n float64) {
+	switch kind {
+	case reflect.Map:
+		// 3.0 中对象属性个数的约束很少被代码生成工具支持，忽略
+	case reflect.String, reflect.Slice, reflect.Array:
+		minimum, maximum := &s.MinLength, &s.MaxLength
+		if kind != reflect.String {
+			minimum, maximum = &s.MinItems, &s.MaxItems
+		}
+		size := int(n)
+		switch tag {
+		case "min", "gte":
+			*minimum = &size
+		case "gt":
+			size++
+			*minimum = &size
+		case "max", "lte":
+			*maximum = &size
+		case "lt":
+			size--
+			*maximum = &size
+		case "len":
+			*minimum, *maximum = &size, &size
+		}
+	default:
+		switch tag {
+		case "min", "gte":
+			s.Minimum = float(n)
+		case "gt":
+			s.Minimum, s.ExclusiveMinimum = float(n), true
+		case "max", "lte":
+			s.Maximum = float(n)
+		case "lt":
+			s.Maximum, s.ExclusiveMaximum = float(n), true
+		case "len":
+			s.Minimum, s.Maximum = float(n), float(n)
+		}
+	}
+}
+
+func enumValue(typ, value string) any {
+	switch typ {
+	case "integer":
+		if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return n
+		}
+	case "number":
+		if n, err := strconv.ParseFloat(value, 64); err == nil {
+			return n
+		}
+	}
+	return value
+}
+
+func float(n float64) *float64 {
+	return &n
+}
+
+func deref(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
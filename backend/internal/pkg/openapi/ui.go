@@ -0,0 +1,35 @@
+package openapi
+
+import (
+	"bytes"
+	_ "embed"
+	"html/template"
+	"net/http"
+
+	swaggerFiles "github.com/swaggo/files/v2"
+)
+
+//go:embed ui.html
+var uiHTML string
+
+var uiTemplate = template.Must(template.New("ui").Parse(uiHTML))
+
+// UIHandler 返回内嵌的 Swagger UI，specURL 为文档地址，可以是相对 UI 页面的路径。
+// 请求路径需去掉挂载路径的前缀
+func UIHandler(title, specURL string) http.Handler {
+	var index bytes.Buffer
+	if err := uiTemplate.Execute(&index, struct{ Title, SpecURL string }{title, specURL}); err != nil {
+		panic(err)
+	}
+	assets := http.FileServer(http.FS(swaggerFiles.FS))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "", "/", "/index.html":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			_, _ = w.Write(index.Bytes())
+		default:
+			assets.ServeHTTP(w, r)
+		}
+	})
+}
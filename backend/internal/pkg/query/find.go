@@ -0,0 +1,185 @@
+package query
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"insight/internal/global"
+	"reflect"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// primaryKey 游标分页的兜底排序字段，保证排序唯一
+const primaryKey = "id"
+
+// Result 分页结果
+type Result struct {
+	Total      int64
+	Page       int
+	PerPage    int
+	NextCursor string
+}
+
+type cursor struct {
+	Orders string `json:"o"`
+	Values []any  `json:"v"`
+	// Times 时间类型的值的下标，时间以 RFC3339Nano 保存，避免按秒截断后翻页停滞
+	Times []int `json:"t,omitempty"`
+}
+
+// Find 按查询条件分页查询到 dest，db 需已指定 Model
+// 偏移分页会统计总数；游标分页不统计总数，最后一页的 NextCursor 为空
+func Find[T any](db *gorm.DB, q *Query, dest *[]T) (*Result, error) {
+	perPage := q.PerPage
+	if perPage < 1 {
+		perPage = global.PerPage
+	}
+	db = db.Session(&gorm.Session{})
+
+	if q.Cursor == nil {
+		res := &Result{Page: max(q.Page, 1), PerPage: perPage}
+		if err := db.Scopes(q.Filter()).Count(&res.Total).Error; err != nil {
+			return nil, err
+		}
+		if res.Total == 0 {
+			return res, nil
+		}
+		err := db.Scopes(q.Filter(), q.Sort(), Paginate(q.Page, perPage)).Find(dest).Error
+		return res, err
+	}
+
+	orders := keysetOrders(q.Orders)
+	tx := db.Scopes(q.Filter())
+	if *q.Cursor != "" {
+		values, err := decodeCursor(*q.Cursor, orders)
+		if err != nil {
+			return nil, err
+		}
+		where, args := keysetWhere(tx, orders, values)
+		tx = tx.Where(where, args...)
+	}
+	for _, o := range orders {
+		tx = tx.Order(orderExpr(tx, o))
+	}
+	// 多取一条判断是否还有下一页
+	tx = tx.Limit(perPage + 1).Find(dest)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+
+	res := &Result{PerPage: perPage}
+	if len(*dest) > perPage {
+		*dest = (*dest)[:perPage]
+		next, err := encodeCursor(tx, orders, (*dest)[perPage-1])
+		if err != nil {
+			return nil, err
+		}
+		res.NextCursor = next
+	}
+	return res, nil
+}
+
+func keysetOrders(orders []Order) []Order {
+	for _, o := range orders {
+		if o.Column == primaryKey {
+			return orders
+		}
+	}
+	return append(orders[:len(orders):len(orders)], Order{Column: primaryKey})
+}
+
+func ordersKey(orders []Order) string {
+	keys := make([]string, len(orders))
+	for i, o := range orders {
+		keys[i] = o.Column
+		if o.Desc {
+			keys[i] = "-" + o.Column
+		}
+	}
+	return strings.Join(keys, ",")
+}
+
+// keysetWhere 生成 (a > ?) OR (a = ? AND b > ?) ... 形式的条件
+func keysetWhere(db *gorm.DB, orders []Order, values []any) (string, []any) {
+	var (
+		ors  []string
+		args []any
+	)
+	for i, o := range orders {
+		ands := make([]string, 0, i+1)
+		for j := 0; j < i; j++ {
+			ands = append(ands, db.Statement.Quote(orders[j].Column)+" = ?")
+			args = append(args, values[j])
+		}
+		cmp := " > ?"
+		if o.Desc {
+			cmp = " < ?"
+		}
+		ands = append(ands, db.Statement.Quote(o.Column)+cmp)
+		args = append(args, values[i])
+		ors = append(ors, "("+strings.Join(ands, " AND ")+")")
+	}
+	return strings.Join(ors, " OR "), args
+}
+
+func encodeCursor(tx *gorm.DB, orders []Order, last any) (string, error) {
+	if tx.Statement.Schema == nil {
+		return "", fmt.Errorf("query: cursor pagination needs a model")
+	}
+	c := cursor{Orders: ordersKey(orders), Values: make([]any, len(orders))}
+	rv := reflect.ValueOf(last)
+	for i, o := range orders {
+		field := tx.Statement.Schema.LookUpField(o.Column)
+		if field == nil {
+			return "", fmt.Errorf("query: unknown cursor column %q", o.Column)
+		}
+		value, _ := field.ValueOf(context.Background(), rv)
+		// 使用写入数据库的值，自定义时间类型的 JSON 格式可能丢失精度
+		if valuer, ok := value.(driver.Valuer); ok {
+			if v, err := valuer.Value(); err == nil {
+				value = v
+			}
+		}
+		if t, ok := value.(time.Time); ok {
+			value = t.Format(time.RFC3339Nano)
+			c.Times = append(c.Times, i)
+		}
+		c.Values[i] = value
+	}
+	b, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func decodeCursor(s string, orders []Order) ([]any, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor", ErrInvalid)
+	}
+	var c cursor
+	if err := json.Unmarshal(b, &c); err != nil || len(c.Values) != len(orders) {
+		return nil, fmt.Errorf("%w: malformed cursor", ErrInvalid)
+	}
+	if c.Orders != ordersKey(orders) {
+		return nil, fmt.Errorf("%w: cursor does not match the sort order", ErrInvalid)
+	}
+	for _, i := range c.Times {
+		if i < 0 || i >= len(c.Values) {
+			return nil, fmt.Errorf("%w: malformed cursor", ErrInvalid)
+		}
+		value, _ := c.Values[i].(string)
+		t, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: malformed cursor", ErrInvalid)
+		}
+		c.Values[i] = t
+	}
+	return c.Values, nil
+}
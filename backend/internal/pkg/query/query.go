@@ -0,0 +1,211 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+	"insight/internal/global"
+	"slices"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// ErrInvalid 查询参数不合法
+var ErrInvalid = errors.New("invalid query")
+
+// Op 过滤操作符
+type Op string
+
+const (
+	Eq      Op = "eq"
+	Ne      Op = "ne"
+	In      Op = "in"
+	Like    Op = "like"
+	Gt      Op = "gt"
+	Gte     Op = "gte"
+	Lt      Op = "lt"
+	Lte     Op = "lte"
+	Between Op = "between"
+)
+
+var comparisons = map[Op]string{Eq: "=", Ne: "<>", Gt: ">", Gte: ">=", Lt: "<", Lte: "<="}
+
+// Field 允许过滤与排序的字段
+type Field struct {
+	Column   string // 数据库列名，为空时与参数名相同
+	Ops      []Op   // 允许的过滤操作符
+	Sortable bool   // 是否允许排序
+}
+
+func (f Field) allows(op Op) bool {
+	for _, o := range f.Ops {
+		if o == op {
+			return true
+		}
+	}
+	return false
+}
+
+// Schema 参数名到字段的白名单，未列出的字段不允许过滤和排序
+type Schema map[string]Field
+
+func (s Schema) lookup(name string) (Field, bool) {
+	f, ok := s[name]
+	if ok && f.Column == "" {
+		f.Column = name
+	}
+	return f, ok
+}
+
+// Condition 过滤条件
+type Condition struct {
+	Column string
+	Op     Op
+	Values []string
+}
+
+// Order 排序字段
+type Order struct {
+	Column string
+	Desc   bool
+}
+
+// Query 解析后的列表查询
+type Query struct {
+	Conditions []Condition
+	Orders     []Order
+	Page       int
+	PerPage    int
+	// Cursor 不为 nil 时使用游标分页，空字符串表示第一页
+	Cursor *string
+}
+
+// Parse 按白名单解析过滤与排序参数
+// 过滤参数格式为 字段:操作符:值，in 的值以逗号分隔，between 的值为 最小值,最大值
+// 排序参数格式为 -字段,字段，- 表示倒序
+func Parse(schema Schema, filters []string, sort string) (*Query, error) {
+	q := &Query{}
+	for _, filter := range filters {
+		parts := strings.SplitN(filter, ":", 3)
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("%w: filter %q should be field:op:value", ErrInvalid, filter)
+		}
+		field, ok := schema.lookup(parts[0])
+		if !ok {
+			return nil, fmt.Errorf("%w: field %q is not filterable", ErrInvalid, parts[0])
+		}
+		op := Op(parts[1])
+		if !field.allows(op) {
+			return nil, fmt.Errorf("%w: operator %q is not allowed on %q", ErrInvalid, op, parts[0])
+		}
+		values := []string{parts[2]}
+		switch op {
+		case In:
+			values = strings.Split(parts[2], ",")
+		case Between:
+			values = strings.Split(parts[2], ",")
+			if len(values) != 2 {
+				return nil, fmt.Errorf("%w: between on %q needs two values", ErrInvalid, parts[0])
+			}
+		}
+		q.Where(field.Column, op, values...)
+	}
+
+	for _, name := range SplitList(sort) {
+		desc := strings.HasPrefix(name, "-")
+		name = strings.TrimPrefix(name, "-")
+		field, ok := schema.lookup(name)
+		if !ok || !field.Sortable {
+			return nil, fmt.Errorf("%w: field %q is not sortable", ErrInvalid, name)
+		}
+		q.Orders = append(q.Orders, Order{Column: field.Column, Desc: desc})
+	}
+	return q, nil
+}
+
+// SplitList 解析逗号分隔的参数，忽略空项
+func SplitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// ParseInclude 按白名单解析 include 参数
+func ParseInclude(allowed []string, include string) (map[string]bool, error) {
+	includes := make(map[string]bool)
+	for _, name := range SplitList(include) {
+		if !slices.Contains(allowed, name) {
+			return nil, fmt.Errorf("%w: %q can not be included", ErrInvalid, name)
+		}
+		includes[name] = true
+	}
+	return includes, nil
+}
+
+// Where 追加过滤条件，用于服务端固定的条件
+func (q *Query) Where(column string, op Op, values ...string) *Query {
+	q.Conditions = append(q.Conditions, Condition{Column: column, Op: op, Values: values})
+	return q
+}
+
+// DefaultOrder 未指定排序时使用的排序
+func (q *Query) DefaultOrder(orders ...Order) *Query {
+	if len(q.Orders) == 0 {
+		q.Orders = orders
+	}
+	return q
+}
+
+// Filter 返回应用过滤条件的 scope
+func (q *Query) Filter() func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		for _, c := range q.Conditions {
+			column := db.Statement.Quote(c.Column)
+			switch c.Op {
+			case In:
+				db = db.Where(column+" IN ?", c.Values)
+			case Like:
+				db = db.Where(column+" LIKE ?", "%"+c.Values[0]+"%")
+			case Between:
+				db = db.Where(column+" BETWEEN ? AND ?", c.Values[0], c.Values[1])
+			default:
+				db = db.Where(column+" "+comparisons[c.Op]+" ?", c.Values[0])
+			}
+		}
+		return db
+	}
+}
+
+// Sort 返回应用排序的 scope
+func (q *Query) Sort() func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		for _, o := range q.Orders {
+			db = db.Order(orderExpr(db, o))
+		}
+		return db
+	}
+}
+
+// Paginate 返回偏移分页的 scope，page 从 1 开始
+func Paginate(page, perPage int) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if page < 1 {
+			page = 1
+		}
+		if perPage < 1 {
+			perPage = global.PerPage
+		}
+		return db.Offset((page - 1) * perPage).Limit(perPage)
+	}
+}
+
+func orderExpr(db *gorm.DB, o Order) string {
+	if o.Desc {
+		return db.Statement.Quote(o.Column) + " DESC"
+	}
+	return db.Statement.Quote(o.Column)
+}
@@ -0,0 +1,181 @@
+package query
+
+import (
+	"insight/internal/pkg/utils"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type item struct {
+	ID    uint `gorm:"primarykey"`
+	Name  string
+	Kind  string
+	Score int
+}
+
+var itemSchema = Schema{
+	"id":    {Ops: []Op{Eq, In}, Sortable: true},
+	"name":  {Ops: []Op{Eq, Like}},
+	"kind":  {Ops: []Op{Eq, In}, Sortable: true},
+	"score": {Ops: []Op{Gte, Lte, Between}, Sortable: true},
+	"type":  {Column: "kind", Ops: []Op{Eq}},
+}
+
+func newTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Discard})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&item{}))
+	items := []item{
+		{Name: "alpha", Kind: "a", Score: 10},
+		{Name: "beta", Kind: "b", Score: 20},
+		{Name: "gamma", Kind: "a", Score: 20},
+		{Name: "delta", Kind: "c", Score: 30},
+		{Name: "epsilon", Kind: "a", Score: 40},
+	}
+	require.NoError(t, db.Create(&items).Error)
+	return db
+}
+
+func names(items []*item) []string {
+	result := make([]string, len(items))
+	for i, it := range items {
+		result[i] = it.Name
+	}
+	return result
+}
+
+func TestParse(t *testing.T) {
+	q, err := Parse(itemSchema, []string{"kind:in:a,b", "score:between:10,20", "type:eq:a", "name:like:a:b"}, "-score, id")
+	require.NoError(t, err)
+	assert.Equal(t, []Condition{
+		{Column: "kind", Op: In, Values: []string{"a", "b"}},
+		{Column: "score", Op: Between, Values: []string{"10", "20"}},
+		{Column: "kind", Op: Eq, Values: []string{"a"}},
+		{Column: "name", Op: Like, Values: []string{"a:b"}},
+	}, q.Conditions)
+	assert.Equal(t, []Order{{Column: "score", Desc: true}, {Column: "id"}}, q.Orders)
+
+	for _, c := range []struct {
+		filters []string
+		sort    string
+	}{
+		{filters: []string{"password:eq:x"}},
+		{filters: []string{"name:gte:x"}},
+		{filters: []string{"name"}},
+		{filters: []string{"score:between:1"}},
+		{sort: "name"},
+	} {
+		_, err := Parse(itemSchema, c.filters, c.sort)
+		assert.ErrorIs(t, err, ErrInvalid, "%v %q", c.filters, c.sort)
+	}
+}
+
+func TestFindOffset(t *testing.T) {
+	db := newTestDB(t)
+	q, err := Parse(itemSchema, []string{"score:gte:20"}, "")
+	require.NoError(t, err)
+	q.DefaultOrder(Order{Column: "score", Desc: true}, Order{Column: "id"})
+	q.Page, q.PerPage = 2, 2
+
+	var items []*item
+	res, err := Find(db.Model(&item{}), q, &items)
+	require.NoError(t, err)
+	assert.EqualValues(t, 4, res.Total)
+	assert.Equal(t, 2, res.Page)
+	assert.Equal(t, []string{"beta", "gamma"}, names(items), "Second page should skip the first two rows")
+
+	// 第一页从头开始
+	q.Page = 1
+	items = nil
+	_, err = Find(db.Model(&item{}), q, &items)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"epsilon", "delta"}, names(items))
+}
+
+func TestFindCursor(t *testing.T) {
+	db := newTestDB(t)
+	q, err := Parse(itemSchema, []string{"kind:in:a,b,c"}, "-score")
+	require.NoError(t, err)
+	q.PerPage = 2
+
+	var (
+		all    []string
+		cursor = ""
+		pages  = 0
+	)
+	for {
+		q.Cursor = &cursor
+		var items []*item
+		res, err := Find(db.Model(&item{}), q, &items)
+		require.NoError(t, err)
+		all = append(all, names(items)...)
+		pages++
+		if res.NextCursor == "" {
+			break
+		}
+		cursor = res.NextCursor
+	}
+	assert.Equal(t, 3, pages)
+	assert.Equal(t, []string{"epsilon", "delta", "beta", "gamma", "alpha"}, all, "Ties should be broken by id")
+
+	// 游标与排序不匹配
+	q.Orders = []Order{{Column: "score"}}
+	var items []*item
+	_, err = Find(db.Model(&item{}), q, &items)
+	assert.ErrorIs(t, err, ErrInvalid)
+
+	bad := "not-a-cursor"
+	q.Cursor = &bad
+	_, err = Find(db.Model(&item{}), q, &items)
+	assert.ErrorIs(t, err, ErrInvalid)
+}
+
+type event struct {
+	ID        uint `gorm:"primarykey"`
+	CreatedAt utils.FormatDate
+}
+
+func TestFindCursorSubSecond(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Discard})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&event{}))
+	// 同一秒内创建的记录，其中两条时间相同
+	base := time.Date(2026, 1, 1, 8, 0, 0, 0, time.Local)
+	for _, offset := range []time.Duration{0, 150, 300, 450, 450, 600} {
+		require.NoError(t, db.Create(&event{CreatedAt: utils.FormatDate{Time: base.Add(offset * time.Millisecond)}}).Error)
+	}
+
+	for _, sort := range []string{"created_at", "-created_at"} {
+		q, err := Parse(Schema{"created_at": {Sortable: true}}, nil, sort)
+		require.NoError(t, err)
+		q.PerPage = 2
+
+		var ids []uint
+		cursor := ""
+		for pages := 0; pages < 10; pages++ {
+			q.Cursor = &cursor
+			var events []*event
+			res, err := Find(db.Model(&event{}), q, &events)
+			require.NoError(t, err)
+			for _, e := range events {
+				ids = append(ids, e.ID)
+			}
+			if res.NextCursor == "" {
+				break
+			}
+			cursor = res.NextCursor
+		}
+		want := []uint{1, 2, 3, 4, 5, 6}
+		if sort == "-created_at" {
+			// 时间相同时按 id 升序
+			want = []uint{6, 4, 5, 3, 2, 1}
+		}
+		assert.Equal(t, want, ids, sort)
+	}
+}
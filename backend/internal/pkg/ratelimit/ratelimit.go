@@ -0,0 +1,30 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Limit 限流规则：每 Period 最多 Limit 次请求
+type Limit struct {
+	Limit  int
+	Period time.Duration
+	// Burst 令牌桶容量，<= 0 时等于 Limit，滑动窗口实现忽略该值
+	Burst int
+}
+
+// Result 一次限流判断的结果，用于输出 RateLimit-* 响应头
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset 配额完全恢复还需的时间
+	Reset time.Duration
+	// RetryAfter 被拒绝时距离下一次可请求的时间
+	RetryAfter time.Duration
+}
+
+// Limiter 限流器
+type Limiter interface {
+	Allow(ctx context.Context, key string, limit Limit) (Result, error)
+}
@@ -0,0 +1,103 @@
+package ratelimit
+
+import (
+	"context"
+	"insight/internal/pkg/cache"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func allowN(t *testing.T, l Limiter, key string, limit Limit, n int) Result {
+	var result Result
+	for i := 0; i < n; i++ {
+		var err error
+		result, err = l.Allow(context.Background(), key, limit)
+		require.NoError(t, err)
+	}
+	return result
+}
+
+func TestMemoryTokenBucket(t *testing.T) {
+	m := NewMemory()
+	now := time.Unix(1000, 0)
+	m.now = func() time.Time { return now }
+	limit := Limit{Limit: 10, Period: 10 * time.Second}
+
+	result := allowN(t, m, "a", limit, 10)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+
+	result = allowN(t, m, "a", limit, 1)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, time.Second, result.RetryAfter)
+	assert.True(t, allowN(t, m, "b", limit, 1).Allowed, "Keys should be counted separately")
+
+	// 每秒补充一个令牌
+	now = now.Add(time.Second)
+	assert.True(t, allowN(t, m, "a", limit, 1).Allowed)
+	assert.False(t, allowN(t, m, "a", limit, 1).Allowed)
+
+	// 回满的桶在清理时移除
+	now = now.Add(time.Minute)
+	allowN(t, m, "c", limit, 1)
+	assert.Len(t, m.buckets, 1)
+}
+
+func TestMemoryBurst(t *testing.T) {
+	m := NewMemory()
+	result := allowN(t, m, "a", Limit{Limit: 60, Period: time.Minute, Burst: 5}, 5)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 5, result.Limit)
+	assert.False(t, allowN(t, m, "a", Limit{Limit: 60, Period: time.Minute, Burst: 5}, 1).Allowed)
+}
+
+func TestSlidingWindow(t *testing.T) {
+	w := NewSlidingWindow(cache.NewMemory(0, 0))
+	now := time.Unix(1000, 0)
+	w.now = func() time.Time { return now }
+	limit := Limit{Limit: 10, Period: 10 * time.Second}
+
+	result := allowN(t, w, "a", limit, 10)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+	assert.Equal(t, 10*time.Second, result.Reset)
+
+	result = allowN(t, w, "a", limit, 1)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, 10*time.Second, result.RetryAfter, "Current window is full")
+
+	// 下一窗口过半时上一窗口按一半计入，被拒绝的请求不计数：10 * 0.5 + 5 <= 10
+	now = now.Add(15 * time.Second)
+	result = allowN(t, w, "a", limit, 5)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+	result = allowN(t, w, "a", limit, 1)
+	assert.False(t, result.Allowed)
+	assert.Greater(t, result.RetryAfter, time.Duration(0))
+	assert.LessOrEqual(t, result.RetryAfter, 5*time.Second)
+
+	// 两个窗口之后计数清零
+	now = now.Add(20 * time.Second)
+	assert.True(t, allowN(t, w, "a", limit, 1).Allowed)
+}
+
+func TestSlidingWindowRetry(t *testing.T) {
+	w := NewSlidingWindow(cache.NewMemory(0, 0))
+	now := time.Unix(1000, 0)
+	w.now = func() time.Time { return now }
+	limit := Limit{Limit: 10, Period: 10 * time.Second}
+
+	require.True(t, allowN(t, w, "a", limit, 10).Allowed)
+	// 超限后每秒重试，一个周期内都被拒绝，周期过后即可恢复
+	for i := 0; i < 10; i++ {
+		result := allowN(t, w, "a", limit, 5)
+		assert.False(t, result.Allowed)
+		now = now.Add(time.Second)
+	}
+	assert.False(t, allowN(t, w, "a", limit, 1).Allowed)
+	now = now.Add(time.Second)
+	assert.True(t, allowN(t, w, "a", limit, 1).Allowed, "Rejected retries should not be counted")
+}
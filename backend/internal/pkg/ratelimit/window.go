@@ -0,0 +1,61 @@
+package ratelimit
+
+import (
+	"context"
+	"insight/internal/pkg/cache"
+	"math"
+	"strconv"
+	"time"
+)
+
+// SlidingWindow 基于缓存计数的滑动窗口，缓存使用 Redis 时多实例共享配额。
+// 按上一窗口计数在当前窗口中的剩余比例估算最近一个 Period 内的请求数。
+type SlidingWindow struct {
+	store  cache.Store
+	prefix string
+	now    func() time.Time
+}
+
+func NewSlidingWindow(store cache.Store) *SlidingWindow {
+	return &SlidingWindow{store: store, prefix: "ratelimit:", now: time.Now}
+}
+
+func (w *SlidingWindow) Allow(ctx context.Context, key string, limit Limit) (Result, error) {
+	now := w.now()
+	window := now.UnixNano() / int64(limit.Period)
+	elapsed := time.Duration(now.UnixNano() % int64(limit.Period))
+
+	// 被拒绝的请求不计数，否则客户端持续重试时上一窗口的计数会一直偏高
+	weight := 1 - float64(elapsed)/float64(limit.Period)
+	current, previous, allowed, err := w.store.IncrWindow(ctx, w.key(key, window), w.key(key, window-1), weight, int64(limit.Limit), 2*limit.Period)
+	if err != nil {
+		return Result{}, err
+	}
+
+	used := float64(previous)*weight + float64(current)
+	result := Result{
+		Allowed:   allowed,
+		Limit:     limit.Limit,
+		Remaining: max(limit.Limit-int(math.Ceil(used)), 0),
+		Reset:     limit.Period - elapsed,
+	}
+	if !result.Allowed {
+		result.RetryAfter = w.retryAfter(previous, current, limit, elapsed)
+	}
+	return result, nil
+}
+
+// retryAfter 估算上一窗口的计数衰减到允许再次请求所需的时间，当前窗口已超限时需等到下一窗口
+func (w *SlidingWindow) retryAfter(previous, current int64, limit Limit, elapsed time.Duration) time.Duration {
+	remainingWindow := limit.Period - elapsed
+	if current >= int64(limit.Limit) || previous == 0 {
+		return remainingWindow
+	}
+	// 下一次请求计入后需满足 previous * (1 - t/period) + current + 1 <= limit
+	t := (1 - float64(int64(limit.Limit)-current-1)/float64(previous)) * float64(limit.Period)
+	return min(max(time.Duration(t)-elapsed, 0), remainingWindow)
+}
+
+func (w *SlidingWindow) key(key string, window int64) string {
+	return w.prefix + key + ":" + strconv.FormatInt(window, 10)
+}
@@ -4,16 +4,27 @@ import (
 	"insight/config"
 	"insight/internal/pkg/errors"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+const (
+	// CodeKey 响应写出后业务码在 gin.Context 中的键，供指标等中间件读取
+	CodeKey = "response_code"
+	// RequestIDKey 请求 ID 在 gin.Context 中的键
+	RequestIDKey = "request_id"
+	// StartTimeKey 请求开始时间在 gin.Context 中的键，用于计算 Cost
+	StartTimeKey = "requestStartTime"
+)
+
 type Result struct {
-	Code int         `json:"code"`
-	Msg  string      `json:"msg"`
-	Data interface{} `json:"data"`
-	Cost string      `json:"cost"`
+	Code      int         `json:"code"`
+	Msg       string      `json:"msg"`
+	Data      interface{} `json:"data"`
+	Cost      string      `json:"cost"`
+	RequestID string      `json:"request_id,omitempty"`
 }
 
 type Response struct {
@@ -98,14 +109,19 @@ func (r *Response) SetMessage(message string) *Response {
 	return r
 }
 
-var ErrorText = errors.NewErrorText(config.GetConfig().System.Language)
+// ErrorText 业务码对应的默认提示，首次响应时按配置的语言创建，导入本包时不读取配置
+var ErrorText = sync.OnceValue(func() *errors.ErrorText {
+	return errors.NewErrorText(config.GetConfig().System.Language)
+})
 
 // json 返回 gin 框架的 HandlerFunc
 func (r *Response) json(c *gin.Context) {
 	if r.result.Msg == "" {
-		r.result.Msg = ErrorText.Text(r.result.Code)
+		r.result.Msg = ErrorText().Text(r.result.Code)
 	}
-	r.result.Cost = time.Since(c.GetTime("requestStartTime")).String()
+	r.result.Cost = time.Since(c.GetTime(StartTimeKey)).String()
+	r.result.RequestID = c.GetString(RequestIDKey)
+	c.Set(CodeKey, r.result.Code)
 	c.AbortWithStatusJSON(r.httpCode, r.result)
 }
 
@@ -0,0 +1,61 @@
+package tracing
+
+import (
+	"errors"
+	"insight/internal/pkg/gormhook"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const gormSpanKey = "tracing:span"
+
+// GormPlugin 为每条语句创建子 span，父 span 取自 db.WithContext 传入的 ctx
+type GormPlugin struct{}
+
+func (GormPlugin) Name() string {
+	return "tracing"
+}
+
+func (p GormPlugin) Initialize(db *gorm.DB) error {
+	return gormhook.Register(db, "tracing", before, after)
+}
+
+func before(operation string, db *gorm.DB) {
+	ctx := db.Statement.Context
+	// 没有父 span 的语句（迁移、后台任务等）不单独开启链路
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return
+	}
+	_, span := Tracer().Start(ctx, "gorm."+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemNameKey.String(db.Dialector.Name()),
+			semconv.DBOperationName(operation),
+		),
+	)
+	db.InstanceSet(gormSpanKey, span)
+}
+
+func after(_ string, db *gorm.DB) {
+	value, ok := db.InstanceGet(gormSpanKey)
+	if !ok {
+		return
+	}
+	span := value.(trace.Span)
+	defer span.End()
+
+	span.SetAttributes(
+		semconv.DBCollectionName(db.Statement.Table),
+		// 只记录带占位符的语句，参数可能包含密码等敏感数据
+		semconv.DBQueryText(db.Statement.SQL.String()),
+		attribute.Int64("db.rows_affected", db.Statement.RowsAffected),
+	)
+	if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+		span.RecordError(db.Error)
+		span.SetStatus(codes.Error, db.Error.Error())
+	}
+}
@@ -0,0 +1,75 @@
+package tracing
+
+import (
+	"context"
+	"insight/config/autoload"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestGormPlugin(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { otel.SetTracerProvider(sdktrace.NewTracerProvider()) })
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Discard})
+	require.NoError(t, err)
+	require.NoError(t, db.Use(GormPlugin{}))
+
+	type item struct {
+		ID   uint
+		Name string
+	}
+	// 没有父 span 时不创建链路
+	require.NoError(t, db.AutoMigrate(&item{}))
+	require.NoError(t, db.Create(&item{Name: "secret"}).Error)
+	assert.Empty(t, recorder.Ended())
+
+	ctx, parent := Tracer().Start(context.Background(), "request")
+	require.NoError(t, db.WithContext(ctx).Where("name = ?", "secret").Find(&[]item{}).Error)
+	parent.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	query := spans[0]
+	assert.Equal(t, "gorm.query", query.Name())
+	assert.Equal(t, parent.SpanContext().SpanID(), query.Parent().SpanID())
+	for _, attr := range query.Attributes() {
+		if attr.Key == "db.query.text" {
+			assert.NotContains(t, attr.Value.AsString(), "secret")
+		}
+	}
+}
+
+func TestInitExporters(t *testing.T) {
+	shutdown, err := Init(context.Background(), autoload.TracingConfig{})
+	require.NoError(t, err)
+	assert.NoError(t, shutdown(context.Background()))
+
+	path := filepath.Join(t.TempDir(), "traces", "traces.json")
+	shutdown, err = Init(context.Background(), autoload.TracingConfig{
+		Enable: true, ServiceName: "test", Exporter: "file", FilePath: path, SampleRatio: 1,
+	})
+	require.NoError(t, err)
+	_, span := Tracer().Start(context.Background(), "job")
+	span.End()
+	require.NoError(t, shutdown(context.Background()))
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `"Name":"job"`)
+
+	_, err = Init(context.Background(), autoload.TracingConfig{Enable: true, Exporter: "zipkin"})
+	assert.Error(t, err)
+	otel.SetTracerProvider(sdktrace.NewTracerProvider())
+}
@@ -30,14 +30,38 @@ func (t FormatDate) Value() (driver.Value, error) {
 	return t.Time, nil
 }
 
+// GormDataType 使用通用的 time 类型，由各数据库方言映射为具体的列类型
+func (t FormatDate) GormDataType() string {
+	return "time"
+}
+
 func (t *FormatDate) Scan(v any) error {
-	if value, ok := v.(time.Time); ok {
+	switch value := v.(type) {
+	case nil:
+		*t = FormatDate{}
+		return nil
+	case time.Time:
 		*t = FormatDate{Time: value}
 		return nil
+	case string:
+		return t.parse(value)
+	case []byte:
+		return t.parse(string(value))
 	}
 	return fmt.Errorf("can not convert %v to timestamp", v)
 }
 
+// parse 兼容以字符串形式返回时间的驱动（如 SQLite）
+func (t *FormatDate) parse(value string) error {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05.999999999-07:00", timeFormat} {
+		if t1, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			*t = FormatDate{Time: t1}
+			return nil
+		}
+	}
+	return fmt.Errorf("can not convert %v to timestamp", value)
+}
+
 func (t *FormatDate) String() string {
 	if t == nil || t.IsZero() {
 		return ""
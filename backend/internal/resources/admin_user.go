@@ -1,7 +1,9 @@
 package resources
 
 import (
+	"encoding/json"
 	"errors"
+	"insight/internal/model"
 
 	"github.com/jinzhu/copier"
 )
@@ -14,17 +16,29 @@ type AdminUserResources struct {
 	Email    string   `json:"email"`
 	Avatar   string   `json:"avatar"`
 	Mobile   string   `json:"mobile"`
+	Status   int8     `json:"status"`
 	Roles    []string `json:"roles"`
-	homePath string   `json:"homePath"`
-	token    string   `json:"token"`
+	Version  uint     `json:"version"`
+	HomePath string   `json:"homePath,omitempty"`
+	Token    string   `json:"token,omitempty"`
+	// RoleDetails 角色详情，请求 include=roles 时返回
+	RoleDetails []*RoleResources `json:"roleDetails,omitempty"`
 }
 
-func NewAdminUserResources(data any) *AdminUserResources {
+func init() {
+	RegisterMapper(NewAdminUserResources)
+}
+
+func NewAdminUserResources(m *model.AdminUser) *AdminUserResources {
 	var adminUser AdminUserResources
-	err := copier.Copy(&adminUser, data)
-	if err != nil {
+	if err := copier.Copy(&adminUser, m); err != nil {
 		return nil
 	}
+	// 角色以 JSON 数组保存，解析失败时按无角色处理
+	_ = json.Unmarshal(m.Roles, &adminUser.Roles)
+	if adminUser.Roles == nil {
+		adminUser.Roles = []string{}
+	}
 	return &adminUser
 }
 
@@ -32,7 +46,7 @@ func (r *AdminUserResources) SetToken(token string) error {
 	if token == "" {
 		return errors.New("token is empty")
 	}
-	r.token = token
+	r.Token = token
 	return nil
 }
 
@@ -1,7 +1,9 @@
 package resources
 
 import (
+	"encoding/json"
 	"insight/internal/global"
+	"insight/internal/pkg/query"
 	"math"
 )
 
@@ -10,9 +12,25 @@ type Paginate struct {
 	PerPage     int   `json:"per_page"`
 	CurrentPage int   `json:"current_page"`
 	LastPage    int   `json:"last_page"`
+	// NextCursor 游标分页时下一页的游标，为空表示没有下一页
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// NewPaginate 由查询结果生成分页信息
+func NewPaginate(result *query.Result) Paginate {
+	return Paginate{
+		Total:       result.Total,
+		PerPage:     result.PerPage,
+		CurrentPage: result.Page,
+		NextCursor:  result.NextCursor,
+	}
 }
 
 func (p *Paginate) calculateLastPage() {
+	// 游标分页不统计总数
+	if p.CurrentPage == 0 && p.Total == 0 {
+		return
+	}
 	if p.CurrentPage < 1 {
 		p.CurrentPage = 1
 	}
@@ -23,15 +41,74 @@ func (p *Paginate) calculateLastPage() {
 	p.LastPage = int(math.Ceil(float64(p.Total) / float64(p.PerPage)))
 }
 
-type Collection struct {
+// Collection 列表响应
+type Collection[T any] struct {
 	Paginate
-	Data []any
+	Data   []T
+	fields []string
 }
 
-func newResponseCollection(paginate Paginate, data []any) *Collection {
+// NewCollection 创建列表响应
+func NewCollection[T any](paginate Paginate, data []T) *Collection[T] {
 	paginate.calculateLastPage()
-	return &Collection{
+	if data == nil {
+		data = []T{}
+	}
+	return &Collection[T]{
 		Paginate: paginate,
 		Data:     data,
 	}
 }
+
+// MapCollection 按注册的转换将模型列表转换为资源列表
+func MapCollection[M, R any](paginate Paginate, models []M) *Collection[R] {
+	data := make([]R, len(models))
+	for i, m := range models {
+		data[i] = Map[M, R](m)
+	}
+	return NewCollection(paginate, data)
+}
+
+// Only 只输出指定的字段，字段为空时输出全部
+func (c *Collection[T]) Only(fields ...string) *Collection[T] {
+	c.fields = fields
+	return c
+}
+
+func (c *Collection[T]) MarshalJSON() ([]byte, error) {
+	type collection struct {
+		Paginate
+		Data any
+	}
+	if len(c.fields) == 0 {
+		return json.Marshal(collection{Paginate: c.Paginate, Data: c.Data})
+	}
+	data := make([]map[string]json.RawMessage, len(c.Data))
+	for i, item := range c.Data {
+		selected, err := selectFields(item, c.fields)
+		if err != nil {
+			return nil, err
+		}
+		data[i] = selected
+	}
+	return json.Marshal(collection{Paginate: c.Paginate, Data: data})
+}
+
+// selectFields 按 JSON 字段名筛选资源的字段
+func selectFields(item any, fields []string) (map[string]json.RawMessage, error) {
+	b, err := json.Marshal(item)
+	if err != nil {
+		return nil, err
+	}
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(b, &all); err != nil {
+		return nil, err
+	}
+	selected := make(map[string]json.RawMessage, len(fields))
+	for _, field := range fields {
+		if v, ok := all[field]; ok {
+			selected[field] = v
+		}
+	}
+	return selected, nil
+}
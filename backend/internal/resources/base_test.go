@@ -0,0 +1,37 @@
+package resources
+
+import (
+	"encoding/json"
+	"insight/internal/model"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectionOnly(t *testing.T) {
+	permissions := []*model.Permission{{Name: "a", Method: "GET"}, {Name: "b", Method: "POST"}}
+	permissions[0].ID, permissions[1].ID = 1, 2
+	collection := MapCollection[*model.Permission, *PermissionResources](Paginate{Total: 2, CurrentPage: 1, PerPage: 10}, permissions)
+
+	b, err := json.Marshal(collection.Only("id", "name", "missing"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"total":2,"per_page":10,"current_page":1,"last_page":1,"Data":[{"id":1,"name":"a"},{"id":2,"name":"b"}]}`, string(b))
+
+	var full struct{ Data []map[string]any }
+	b, err = json.Marshal(collection.Only())
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(b, &full))
+	assert.Equal(t, "POST", full.Data[1]["method"], "All fields should be returned without a selection")
+}
+
+func TestToResource(t *testing.T) {
+	user := &model.AdminUser{Username: "admin", Password: "secret", Roles: []byte(`["admin"]`)}
+	res, ok := ToResource(user).(*AdminUserResources)
+	require.True(t, ok)
+	assert.Equal(t, []string{"admin"}, res.Roles)
+
+	menu := model.Menu{}
+	assert.Equal(t, menu, ToResource(menu), "Unregistered models should be returned as is")
+	assert.Panics(t, func() { Map[model.Menu, *RoleResources](menu) })
+}
@@ -0,0 +1,33 @@
+package resources
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// mappers 模型类型到资源转换函数
+var mappers = map[reflect.Type]func(any) any{}
+
+// RegisterMapper 注册模型到资源的转换，在 init 中调用
+func RegisterMapper[M, R any](fn func(M) R) {
+	mappers[reflect.TypeFor[M]()] = func(m any) any {
+		return fn(m.(M))
+	}
+}
+
+// Map 将模型转换为资源，未注册转换时 panic
+func Map[M, R any](m M) R {
+	fn, ok := mappers[reflect.TypeFor[M]()]
+	if !ok {
+		panic(fmt.Sprintf("resources: no mapper registered for %s", reflect.TypeFor[M]()))
+	}
+	return fn(m).(R)
+}
+
+// ToResource 按模型的实际类型转换为资源，未注册转换时原样返回
+func ToResource(m any) any {
+	if fn, ok := mappers[reflect.TypeOf(m)]; ok {
+		return fn(m)
+	}
+	return m
+}
@@ -1,5 +1,7 @@
 package resources
 
+import "insight/internal/model"
+
 type PermissionResources struct {
 	ID       uint   `json:"id"`
 	Name     string `json:"name"`      // 权限名称
@@ -10,35 +12,24 @@ type PermissionResources struct {
 	FuncPath string `json:"func_path"` // 接口方法
 	IsAuth   int8   `json:"is_auth"`   // 接口方法
 	Sort     int32  `json:"sort"`      // 排序
+	Version  uint   `json:"version"`   // 版本号
 }
 
-func NewPermissionResources() *PermissionResources {
-	return &PermissionResources{}
-}
-
-type PermissionCollection struct {
-	Paginate
-	Data []*PermissionResources
-}
-
-func NewPermissionCollection() *PermissionCollection {
-	return &PermissionCollection{}
+func init() {
+	RegisterMapper(NewPermissionResources)
 }
 
-func (p *PermissionCollection) ToCollection() *Collection {
-	data := make([]any, 0, len(p.Data))
-	for _, v := range p.Data {
-		data = append(data, &PermissionResources{
-			ID:       v.ID,
-			Name:     v.Name,
-			Desc:     v.Desc,
-			Method:   v.Method,
-			Route:    v.Route,
-			Func:     v.Func,
-			FuncPath: v.FuncPath,
-			IsAuth:   v.IsAuth,
-			Sort:     v.Sort,
-		})
+func NewPermissionResources(m *model.Permission) *PermissionResources {
+	return &PermissionResources{
+		ID:       m.ID,
+		Name:     m.Name,
+		Desc:     m.Desc,
+		Method:   m.Method,
+		Route:    m.Route,
+		Func:     m.Func,
+		FuncPath: m.FuncPath,
+		IsAuth:   m.IsAuth,
+		Sort:     m.Sort,
+		Version:  m.Version,
 	}
-	return newResponseCollection(p.Paginate, data)
 }
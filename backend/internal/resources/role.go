@@ -0,0 +1,29 @@
+package resources
+
+import "insight/internal/model"
+
+type RoleResources struct {
+	ID      uint   `json:"id"`
+	Code    string `json:"code"`   // 角色标识
+	Name    string `json:"name"`   // 角色名称
+	Desc    string `json:"desc"`   // 角色描述
+	Status  int8   `json:"status"` // 状态
+	Sort    int32  `json:"sort"`   // 排序
+	Version uint   `json:"version"`
+}
+
+func init() {
+	RegisterMapper(NewRoleResources)
+}
+
+func NewRoleResources(m *model.Role) *RoleResources {
+	return &RoleResources{
+		ID:      m.ID,
+		Code:    m.Code,
+		Name:    m.Name,
+		Desc:    m.Desc,
+		Status:  m.Status,
+		Sort:    m.Sort,
+		Version: m.Version,
+	}
+}
@@ -0,0 +1,9 @@
+package resources
+
+import "insight/internal/pkg/utils"
+
+// TrashResources 回收站中的记录
+type TrashResources struct {
+	DeletedAt utils.FormatDate `json:"deleted_at"` // 删除时间
+	Record    any              `json:"record"`     // 删除前的记录
+}
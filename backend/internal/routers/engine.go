@@ -0,0 +1,63 @@
+package routers
+
+import (
+	"fmt"
+	"insight/internal/container"
+	"insight/internal/middleware"
+	"insight/internal/pkg/metrics"
+	"insight/internal/routers/groups"
+	"insight/internal/routers/setup"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+)
+
+// NewEngine creates the gin engine with the global middleware chain and every route,
+// shared by the HTTP server and the test harness so that both serve the same stack.
+// It fails when system.trusted_proxies contains an invalid IP or CIDR.
+func NewEngine(deps *container.Container) (*gin.Engine, error) {
+	cfg := deps.Config()
+	// 不使用 gin.Default 的文本日志，访问日志统一由 zap 输出
+	r := gin.New()
+	// 只信任配置的代理传入的 X-Forwarded-For，否则客户端可以伪造限流与访问日志使用的 IP
+	if err := r.SetTrustedProxies(cfg.System.TrustedProxies); err != nil {
+		return nil, fmt.Errorf("invalid system.trusted_proxies: %w", err)
+	}
+
+	// 请求指标，放在最前面以统计完整耗时
+	if cfg.Metrics.Enable {
+		r.Use(middleware.Metrics())
+		r.GET(cfg.Metrics.Path, gin.WrapH(metrics.Handler()))
+	}
+	// 链路追踪，span 名称使用路由模板
+	if cfg.Tracing.Enable {
+		r.Use(otelgin.Middleware(cfg.Tracing.ServiceName))
+	}
+	// 请求 ID 与请求级日志
+	r.Use(middleware.RequestID())
+	r.Use(middleware.SecureHeaders())
+	// 访问日志，需在 panic 恢复之前以记录 500 响应
+	r.Use(middleware.AccessLog())
+	r.Use(middleware.Recovery())
+	// 配置CORS中间件
+	r.Use(middleware.CorsHandler())
+	// 客户端证书身份
+	if cfg.TLS.Enable {
+		r.Use(middleware.ClientCert())
+	}
+	// 写操作后的读请求走主库
+	r.Use(middleware.ReadYourWrites())
+
+	SetupRouter(r, deps)
+	return r, nil
+}
+
+// NewDebugEngine creates the engine for the separate debug.addr listener. It serves only the
+// diagnostics under "/debug", the paths go tool pprof expects, and relies on the listener
+// being bound to a loopback address instead of on authentication.
+func NewDebugEngine(deps *container.Container) *gin.Engine {
+	r := gin.New()
+	r.Use(middleware.Recovery())
+	groups.DebugRouters(r.Group("/debug"), *setup.NewControllers(deps))
+	return r
+}
@@ -12,9 +12,11 @@ func AdminRouters(router *gin.RouterGroup, controller setup.Controllers) {
 
 	// User management routes
 	userGroup := adminGroup.Group("/users")
-	userGroup.Use(middleware.AdminAuthHandler())
+	userGroup.Use(controller.AdminAuth, middleware.RateLimit("user"))
 	{
+		userGroup.GET("/", controller.UserController.List)
 		userGroup.POST("/", controller.UserController.Add)
+		userGroup.PUT("/", controller.UserController.Update)
 		userGroup.DELETE("/", controller.UserController.Delete)
 		userGroup.GET("/info", controller.UserController.GetUserInfo)
 	}
@@ -22,13 +24,28 @@ func AdminRouters(router *gin.RouterGroup, controller setup.Controllers) {
 	// Login routes
 	loginGroup := adminGroup.Group("/login")
 	{
-		loginGroup.POST("/", controller.LoginController.Login)
+		loginGroup.POST("/", middleware.RateLimit("login"), controller.LoginController.Login)
 	}
 
 	// Permission management routes
 	permissionGroup := adminGroup.Group("/permissions")
+	permissionGroup.Use(controller.AdminAuth, middleware.RateLimit("user"))
 	{
 		permissionGroup.POST("/", controller.PermissionController.Edit)
 		permissionGroup.GET("/", controller.PermissionController.List)
+		permissionGroup.GET("/:id", controller.PermissionController.Detail)
 	}
+
+	// Recycle bin routes, resource is one of model.TrashResources()
+	trashGroup := adminGroup.Group("/trash/:resource")
+	trashGroup.Use(controller.AdminAuth, middleware.RateLimit("user"))
+	{
+		trashGroup.GET("/", controller.TrashController.List)
+		trashGroup.POST("/restore", controller.TrashController.Restore)
+		// Purging cannot be undone, so it is limited to super admins
+		trashGroup.DELETE("/", controller.SuperAdmin, controller.TrashController.Purge)
+	}
+
+	// Dependency status for admins, probes use /healthz and /readyz
+	adminGroup.GET("/health", controller.AdminAuth, middleware.RateLimit("user"), controller.HealthController.Status)
 }
@@ -0,0 +1,20 @@
+package groups
+
+import (
+	"insight/internal/routers/setup"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DebugRouters registers pprof and runtime diagnostics on the given group.
+// The routes carry no authentication of their own; the caller either guards the
+// group with AdminAuth and SuperAdmin or serves it on a localhost-only listener.
+func DebugRouters(router *gin.RouterGroup, controller setup.Controllers) {
+	router.GET("/pprof/", controller.DebugController.PprofIndex)
+	router.GET("/pprof/:name", controller.DebugController.Pprof)
+	// go tool pprof resolves symbols with POST
+	router.POST("/pprof/:name", controller.DebugController.Pprof)
+	router.GET("/goroutines", controller.DebugController.Goroutines)
+	router.GET("/runtime", controller.DebugController.Runtime)
+	router.GET("/database", controller.DebugController.Database)
+}
@@ -1,6 +1,7 @@
 package groups
 
 import (
+	"insight/internal/middleware"
 	"insight/internal/routers/setup"
 
 	"github.com/gin-gonic/gin"
@@ -8,4 +9,6 @@ import (
 
 func DemoRouters(router *gin.RouterGroup, controller setup.Controllers) {
 	router.GET("/demo", controller.DemoController.Demo)
+	// 调用 agent 开销较大，使用更严格的限流策略
+	router.GET("/demo/agent", middleware.RateLimit("agent"), controller.DemoController.Agent)
 }
@@ -0,0 +1,13 @@
+package groups
+
+import (
+	"insight/internal/routers/setup"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DocsRouters registers the OpenAPI document and the Swagger UI, which loads the document from ../openapi.json.
+func DocsRouters(router *gin.RouterGroup, controller setup.Controllers) {
+	router.GET("/openapi.json", controller.DocsController.Spec)
+	router.GET("/docs/*filepath", controller.DocsController.UI)
+}
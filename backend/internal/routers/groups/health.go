@@ -0,0 +1,13 @@
+package groups
+
+import (
+	"insight/internal/routers/setup"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HealthRouters registers the liveness and readiness probes outside the /api prefix.
+func HealthRouters(router *gin.RouterGroup, controller setup.Controllers) {
+	router.GET("/healthz", controller.HealthController.Healthz)
+	router.GET("/readyz", controller.HealthController.Readyz)
+}
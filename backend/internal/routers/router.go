@@ -1,20 +1,116 @@
 package routers
 
 import (
+	"insight/internal/container"
+	"insight/internal/global"
+	"insight/internal/middleware"
+	"insight/internal/pkg/deprecation"
+	"insight/internal/pkg/openapi"
 	"insight/internal/routers/groups"
 	"insight/internal/routers/setup"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+// Version is a mounted API version. The same route group may be mounted under several
+// versions; a version that changes some routes incompatibly replaces that group in its list.
+type Version struct {
+	Name    string // path prefix /api/<Name>, empty for the unversioned /api routes
+	Routers []func(*gin.RouterGroup, setup.Controllers)
+	// Deprecated marks every route of the version as deprecated, pointing to the
+	// same path under the Successor version.
+	Deprecated *deprecation.Policy
+	Successor  string
+}
+
+var appRouters = []func(*gin.RouterGroup, setup.Controllers){
+	groups.HelloRouters,
+	groups.DemoRouters,
+	groups.AdminRouters,
+}
+
+var versions = []Version{
+	{Name: "v1", Routers: appRouters},
+	{Name: "v2", Routers: appRouters},
+	// The unversioned routes predate versioning and are equivalent to v1
+	{
+		Routers: appRouters,
+		Deprecated: &deprecation.Policy{
+			Since:  time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC),
+			Sunset: time.Date(2027, 4, 30, 0, 0, 0, 0, time.UTC),
+		},
+		Successor: "v1",
+	},
+}
+
 // SetupRouter registers API routes on the provided gin.Engine.
-// It creates controller instances via setup.NewControllers(), mounts the
-// "/api" route group on the given router, and registers application routes
-// (currently groups.HelloRouters) onto that group.
-func SetupRouter(router *gin.Engine) {
-	Controllers := setup.NewControllers()
+// It creates controller instances from the services of deps via setup.NewControllers,
+// binds the database of deps to every request, mounts the "/api" route group on the
+// given router, and registers the route groups of every version onto "/api/<version>".
+// Diagnostics are mounted on "/admin/debug" for super admins unless debug.addr is set.
+func SetupRouter(router *gin.Engine, deps *container.Container) {
+	router.Use(middleware.Database(deps.DB()))
+	Controllers := setup.NewControllers(deps)
+	Controllers.DocsController.SetSpec(func() *openapi.Document { return OpenAPI(router.Routes()) })
+	groups.HealthRouters(&router.RouterGroup, *Controllers)
+	// With debug.addr set the diagnostics are served by NewDebugEngine instead
+	if debug := deps.Config().Debug; debug.Enable && debug.Addr == "" {
+		groups.DebugRouters(router.Group("/admin/debug", Controllers.AdminAuth, Controllers.SuperAdmin), *Controllers)
+	}
 	api := router.Group("/api")
-	groups.HelloRouters(api, *Controllers)
-	groups.DemoRouters(api, *Controllers)
-	groups.AdminRouters(api, *Controllers)
+	api.Use(middleware.RateLimit("default"), middleware.Deprecation())
+	if deps.Config().OpenAPI.Enable {
+		groups.DocsRouters(api, *Controllers)
+	}
+	for _, version := range versions {
+		mountVersion(router, api, version, *Controllers)
+	}
+}
+
+func mountVersion(router *gin.Engine, api *gin.RouterGroup, version Version, controller setup.Controllers) {
+	group := api
+	if version.Name != "" {
+		group = api.Group("/" + version.Name)
+	}
+	mount := func() {
+		for _, routers := range version.Routers {
+			routers(group, controller)
+		}
+	}
+	if version.Deprecated == nil {
+		mount()
+		return
+	}
+	deprecateNew(router, mount, func(route gin.RouteInfo) deprecation.Policy {
+		policy := *version.Deprecated
+		if version.Successor != "" {
+			policy.Successor = api.BasePath() + "/" + version.Successor + strings.TrimPrefix(route.Path, group.BasePath())
+		}
+		return policy
+	})
+}
+
+// deprecateNew marks every route added by register as deprecated with the policy returned by policyOf.
+func deprecateNew(router *gin.Engine, register func(), policyOf func(gin.RouteInfo) deprecation.Policy) {
+	existing := map[string]bool{}
+	for _, route := range router.Routes() {
+		existing[route.Method+" "+route.Path] = true
+	}
+	register()
+	for _, route := range router.Routes() {
+		if !existing[route.Method+" "+route.Path] {
+			deprecation.Register(route.Method, route.Path, policyOf(route))
+		}
+	}
+}
+
+// OpenAPI builds the OpenAPI document of the given routes, see openapi.Describe for per-handler details.
+func OpenAPI(routes gin.RoutesInfo) *openapi.Document {
+	return openapi.Build(routes, openapi.Info{
+		Title:       "Insight API",
+		Description: "除健康检查外，响应均使用统一的 Result 结构，业务码 code 为 0 表示成功。",
+		Version:     global.Version,
+	})
 }
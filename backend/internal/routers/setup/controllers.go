@@ -1,9 +1,16 @@
 package setup
 
 import (
+	"insight/internal/container"
 	"insight/internal/controller/admin"
+	"insight/internal/controller/debug"
 	"insight/internal/controller/demo"
+	"insight/internal/controller/docs"
+	"insight/internal/controller/health"
 	"insight/internal/controller/hello"
+	"insight/internal/middleware"
+
+	"github.com/gin-gonic/gin"
 )
 
 type Controllers struct {
@@ -13,28 +20,33 @@ type Controllers struct {
 	LoginController      admin.LoginController
 	PermissionController admin.PermissionController
 	RoleController       admin.RoleController
-}
-
-// NewControllers creates and returns a Controllers instance with its HelloController
-// field initialized.
-//
-// It instantiates a hello.HelloController via hello.NewHelloController and returns
-// a pointer to the populated Controllers struct.
-func NewControllers() *Controllers {
+	TrashController      admin.TrashController
+	HealthController     health.HealthController
+	DocsController       docs.DocsController
+	DebugController      debug.DebugController
 
-	HelloController := hello.NewHelloController()
-	DemoController := demo.NewDemoController()
-	UserController := admin.NewAdminUserController()
-	LoginController := admin.NewLoginController()
-	PermissionController := admin.NewPermissionController()
-	RoleController := admin.NewRoleController()
+	// AdminAuth is the admin login middleware, shared by every admin route group.
+	AdminAuth gin.HandlerFunc
+	// SuperAdmin restricts a route group to super admins; it must follow AdminAuth.
+	SuperAdmin gin.HandlerFunc
+}
 
+// NewControllers creates the controllers with the services resolved from deps,
+// so route groups never construct services themselves.
+func NewControllers(deps *container.Container) *Controllers {
 	return &Controllers{
-		HelloController:      *HelloController,
-		DemoController:       *DemoController,
-		UserController:       *UserController,
-		LoginController:      *LoginController,
-		PermissionController: *PermissionController,
-		RoleController:       *RoleController,
+		HelloController:      *hello.NewHelloController(deps.HelloService()),
+		DemoController:       *demo.NewDemoController(deps.DemoService()),
+		UserController:       *admin.NewAdminUserController(deps.AdminUserService()),
+		LoginController:      *admin.NewLoginController(deps.LoginService()),
+		PermissionController: *admin.NewPermissionController(deps.PermissionService()),
+		RoleController:       *admin.NewRoleController(),
+		TrashController:      *admin.NewTrashController(deps.TrashService()),
+		HealthController:     *health.NewHealthController(),
+		DocsController:       *docs.NewDocsController(),
+		DebugController:      *debug.NewDebugController(deps.DB()),
+
+		AdminAuth:  middleware.AdminAuthHandler(deps.AdminUserService(), deps.LoginService(), deps.Clock()),
+		SuperAdmin: middleware.SuperAdmin(deps.AdminUserService()),
 	}
 }
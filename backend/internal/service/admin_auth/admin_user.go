@@ -1,35 +1,176 @@
 package admin_auth
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"insight/internal/model"
-	"insight/internal/pkg/errors"
+	"insight/internal/pkg/cache"
+	e "insight/internal/pkg/errors"
+	"insight/internal/pkg/query"
 	"insight/internal/resources"
 	"insight/internal/service"
+	"insight/internal/validator/form"
+	"slices"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
+// AdminUserTag 用户相关缓存的标签，用户变更时按标签失效
+func AdminUserTag(id uint) string {
+	return fmt.Sprintf("admin_user:%d", id)
+}
+
 // AdminUserService 授权服务
 type AdminUserService struct {
 	service.Base
+	store cache.Store
+	// active 缓存用户是否可用，避免鉴权时每次查询数据库
+	active *cache.Typed[bool]
 }
 
-func NewAdminUserService() *AdminUserService {
-	return &AdminUserService{}
+func NewAdminUserService(logger *zap.Logger, store cache.Store) *AdminUserService {
+	return &AdminUserService{
+		Base:   service.NewBase(logger),
+		store:  store,
+		active: cache.NewTyped[bool](store, time.Minute),
+	}
 }
 
 // GetUserInfo 获取用户信息
-func (s *AdminUserService) GetUserInfo(id uint) (*resources.AdminUserResources, error) {
+func (s *AdminUserService) GetUserInfo(ctx context.Context, id uint) (*resources.AdminUserResources, error) {
 	// 查询用户是否存在
 	adminUsersModel := model.NewAdminUsers()
-	user := adminUsersModel.GetUserById(id)
-	if user != nil {
-		result := resources.NewAdminUserResources(*user)
+	user, err := adminUsersModel.GetUserById(ctx, id)
+	if err == nil {
+		result := resources.NewAdminUserResources(user)
 		var roles []string
 		if err := json.Unmarshal(user.Roles, &roles); err != nil {
-			return nil, errors.NewBusinessError(errors.FAILURE, "获取用户信息失败")
+			return nil, e.NewBusinessError(e.FAILURE, "获取用户信息失败")
 		}
 		result.SetRoles(roles)
 		return result, nil
 	}
-	return nil, errors.NewBusinessError(errors.FAILURE, "获取用户信息失败")
+	return nil, e.NewBusinessError(e.FAILURE, "获取用户信息失败")
+}
+
+// adminUserIncludes 用户列表可以返回的关联资源
+var adminUserIncludes = []string{"roles"}
+
+// List 用户列表，include=roles 时返回角色详情
+func (s *AdminUserService) List(ctx context.Context, params *form.ListQuery) (*resources.Collection[*resources.AdminUserResources], error) {
+	q, err := query.Parse(model.AdminUserQuery, params.Filter, params.Sort)
+	if err != nil {
+		return nil, e.NewBusinessError(e.InvalidParameter, err.Error())
+	}
+	includes, err := query.ParseInclude(adminUserIncludes, params.Include)
+	if err != nil {
+		return nil, e.NewBusinessError(e.InvalidParameter, err.Error())
+	}
+	q.DefaultOrder(query.Order{Column: "id", Desc: true})
+	q.Page, q.PerPage, q.Cursor = params.Page, params.PerPage, params.Cursor
+
+	rows, result, err := model.NewAdminUsers().ListPage(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	collection := resources.MapCollection[*model.AdminUser, *resources.AdminUserResources](resources.NewPaginate(result), rows)
+	if includes["roles"] {
+		if err := s.includeRoles(ctx, collection.Data); err != nil {
+			return nil, err
+		}
+	}
+	return collection.Only(query.SplitList(params.Fields)...), nil
+}
+
+// includeRoles 一次查询所有用户的角色并填充角色详情
+func (s *AdminUserService) includeRoles(ctx context.Context, users []*resources.AdminUserResources) error {
+	var codes []string
+	for _, user := range users {
+		codes = append(codes, user.Roles...)
+	}
+	roles, err := model.NewRole().GetByCodes(ctx, codes)
+	if err != nil {
+		return err
+	}
+	byCode := make(map[string]*resources.RoleResources, len(roles))
+	for _, role := range roles {
+		byCode[role.Code] = resources.NewRoleResources(role)
+	}
+	for _, user := range users {
+		user.RoleDetails = make([]*resources.RoleResources, 0, len(user.Roles))
+		for _, code := range user.Roles {
+			if role, ok := byCode[code]; ok {
+				user.RoleDetails = append(user.RoleDetails, role)
+			}
+		}
+	}
+	return nil
+}
+
+// IsActive 判断用户是否存在且未禁用，结果缓存一分钟
+func (s *AdminUserService) IsActive(ctx context.Context, id uint) (bool, error) {
+	return s.active.GetOrLoad(ctx, fmt.Sprintf("admin_user:active:%d", id), func(ctx context.Context) (bool, error) {
+		user, err := model.NewAdminUsers().GetUserById(ctx, id)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		// 查询失败时不缓存结果，避免数据库抖动导致用户在缓存期内无法登录
+		if err != nil {
+			return false, err
+		}
+		return user.Status == 1, nil
+	}, AdminUserTag(id))
+}
+
+// IsSuperAdmin 判断用户是否拥有超级管理员角色
+func (s *AdminUserService) IsSuperAdmin(ctx context.Context, id uint) (bool, error) {
+	user, err := model.NewAdminUsers().GetUserById(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if len(user.Roles) == 0 {
+		return false, nil
+	}
+	var roles []string
+	if err := json.Unmarshal(user.Roles, &roles); err != nil {
+		return false, err
+	}
+	return slices.Contains(roles, model.SuperAdminRole), nil
+}
+
+// Invalidate 清除用户相关缓存
+func (s *AdminUserService) Invalidate(ctx context.Context, id uint) error {
+	return s.store.InvalidateTags(ctx, AdminUserTag(id))
+}
+
+// Update 按版本号更新用户资料，版本冲突时返回服务端的当前数据
+func (s *AdminUserService) Update(ctx context.Context, params *form.EditAdminUserForm) error {
+	data := map[string]any{
+		"nick_name": params.NickName,
+		"mobile":    params.Mobile,
+		"email":     params.Email,
+		"avatar":    params.Avatar,
+	}
+	if params.Status != nil {
+		data["status"] = *params.Status
+	}
+	err := model.NewAdminUsers().Update(ctx, params.ID, params.Version, data)
+	if errors.Is(err, model.ErrVersionConflict) {
+		current, err := s.GetUserInfo(ctx, params.ID)
+		if err != nil {
+			return e.NewBusinessError(e.NotFound)
+		}
+		return e.NewBusinessError(e.Conflict).WithData(current)
+	}
+	if err != nil {
+		return err
+	}
+	return s.Invalidate(ctx, params.ID)
 }
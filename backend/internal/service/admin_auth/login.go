@@ -1,12 +1,16 @@
 package admin_auth
 
 import (
-	c "insight/config"
+	"context"
+	"insight/config/autoload"
 	"insight/internal/model"
+	"insight/internal/pkg/clock"
 	e "insight/internal/pkg/errors"
 	"insight/internal/pkg/utils/token"
 	"insight/internal/service"
 	"time"
+
+	"go.uber.org/zap"
 )
 
 // TokenResponse token响应结构体
@@ -18,16 +22,18 @@ type TokenResponse struct {
 
 type LoginService struct {
 	service.Base
+	jwt   autoload.JwtConfig
+	clock clock.Clock
 }
 
-func NewLoginService() *LoginService {
-	return &LoginService{}
+func NewLoginService(logger *zap.Logger, jwt autoload.JwtConfig, clk clock.Clock) *LoginService {
+	return &LoginService{Base: service.NewBase(logger), jwt: jwt, clock: clk}
 }
 
-func (s *LoginService) Login(username, password string) (*TokenResponse, error) {
+func (s *LoginService) Login(ctx context.Context, username, password string) (*TokenResponse, error) {
 	adminUserModel := model.NewAdminUsers()
 	// 检查用户是否存在
-	user := adminUserModel.GetUserInfo(username)
+	user := adminUserModel.GetUserInfo(ctx, username)
 
 	if user == nil {
 		err := e.NewBusinessError(e.UserDoesNotExist)
@@ -51,17 +57,17 @@ func (s *LoginService) Login(username, password string) (*TokenResponse, error)
 	}
 	return &TokenResponse{
 		AccessToken: accessToken,
-		TokenType:   c.GetConfig().Jwt.HeaderPrefix,
+		TokenType:   s.jwt.HeaderPrefix,
 		ExpiresAt:   claims.ExpiresAt.Unix(),
 	}, nil
 }
 
 // Refresh 刷新token
-func (s *LoginService) Refresh(id uint) (*TokenResponse, error) {
+func (s *LoginService) Refresh(ctx context.Context, id uint) (*TokenResponse, error) {
 	// 查询用户是否存在
 	adminUsersModel := model.NewAdminUsers()
-	user := adminUsersModel.GetUserById(id)
-	if user == nil {
+	user, err := adminUsersModel.GetUserById(ctx, id)
+	if err != nil {
 		return nil, e.NewBusinessError(e.FAILURE, "更新用户异常")
 	}
 
@@ -72,14 +78,14 @@ func (s *LoginService) Refresh(id uint) (*TokenResponse, error) {
 	}
 	return &TokenResponse{
 		AccessToken: accessToken,
-		TokenType:   c.GetConfig().Jwt.HeaderPrefix,
+		TokenType:   s.jwt.HeaderPrefix,
 		ExpiresAt:   claims.ExpiresAt.Unix(),
 	}, nil
 
 }
 
 func (s *LoginService) NewAdminCustomClaims(user *model.AdminUser) token.AdminCustomClaims {
-	now := time.Now()
-	expiresAt := now.Add(time.Second * c.GetConfig().Jwt.TTL)
+	now := s.clock.Now()
+	expiresAt := now.Add(time.Second * s.jwt.TTL)
 	return token.NewAdminCustomClaims(user, expiresAt)
 }
@@ -1,24 +1,31 @@
 package admin_auth
 
 import (
+	"context"
+	"errors"
+	d "insight/data"
 	"insight/internal/model"
 	e "insight/internal/pkg/errors"
+	"insight/internal/pkg/query"
 	"insight/internal/resources"
 	"insight/internal/service"
 	"insight/internal/validator/form"
-	"strings"
+	"strconv"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
 type PermissionService struct {
 	service.Base
 }
 
-func NewPermissionService() *PermissionService {
-	return &PermissionService{}
+func NewPermissionService(logger *zap.Logger) *PermissionService {
+	return &PermissionService{Base: service.NewBase(logger)}
 }
 
 // Edit 编辑权限
-func (s *PermissionService) Edit(params *form.EditPermission) error {
+func (s *PermissionService) Edit(ctx context.Context, params *form.EditPermission) error {
 	permissionModel := model.NewPermission()
 	data := map[string]any{
 		"name":    params.Name,
@@ -27,48 +34,75 @@ func (s *PermissionService) Edit(params *form.EditPermission) error {
 		"sort":    params.Sort,
 	}
 	if params.Id > 0 {
-		return permissionModel.Update(params.Id, data)
+		err := permissionModel.Update(ctx, params.Id, params.Version, data)
+		if errors.Is(err, model.ErrVersionConflict) {
+			return s.conflict(ctx, params.Id)
+		}
+		return err
 	}
 	data["func"] = params.Func
 	data["func_path"] = params.FuncPath
 	data["method"] = params.Method
 	data["route"] = params.Route
-	count, err := permissionModel.HasRoute(params.Route)
-	if err != nil {
-		return err
+	// 检查与写入在同一事务中完成
+	return d.Transaction(ctx, func(ctx context.Context) error {
+		count, err := permissionModel.HasRoute(ctx, params.Method, params.Route)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return e.NewBusinessError(1, "权限路由已存在")
+		}
+		return permissionModel.Create(ctx, data)
+	})
+}
+
+// Detail 权限详情
+func (s *PermissionService) Detail(ctx context.Context, id uint) (*resources.PermissionResources, error) {
+	permission, err := model.NewPermission().GetById(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, e.NewBusinessError(e.NotFound)
 	}
-	if count > 0 {
-		return e.NewBusinessError(1, "权限路由已存在")
+	if err != nil {
+		return nil, err
 	}
-	return permissionModel.Create(data)
+	return resources.NewPermissionResources(permission), nil
 }
 
-func (s *PermissionService) ListPage(permission *form.ListPermission) *resources.Collection {
-	var condition strings.Builder
-	var args []any
+// conflict 版本冲突时返回服务端的当前数据
+func (s *PermissionService) conflict(ctx context.Context, id uint) error {
+	current, err := s.Detail(ctx, id)
+	if err != nil {
+		return err
+	}
+	return e.NewBusinessError(e.Conflict).WithData(current)
+}
 
-	if permission.Name != "" {
-		condition.WriteString("name LIKE ? AND ")
-		args = append(args, "%"+permission.Name+"%")
+// ListPage 权限列表，name、method、route、is_auth 为 filter 的简写
+func (s *PermissionService) ListPage(ctx context.Context, params *form.ListPermission) (*resources.Collection[*resources.PermissionResources], error) {
+	q, err := query.Parse(model.PermissionQuery, params.Filter, params.Sort)
+	if err != nil {
+		return nil, e.NewBusinessError(e.InvalidParameter, err.Error())
 	}
-	if permission.Method != "" {
-		condition.WriteString("method = ? AND ")
-		args = append(args, permission.Method)
+	if params.Name != "" {
+		q.Where("name", query.Like, params.Name)
 	}
-	if permission.Route != "" {
-		condition.WriteString("route LIKE ? AND ")
-		args = append(args, "%"+permission.Route+"%")
+	if params.Method != "" {
+		q.Where("method", query.Eq, params.Method)
 	}
-	if permission.IsAuth >= 0 {
-		condition.WriteString("is_auth = ? AND ")
-		args = append(args, permission.IsAuth)
+	if params.Route != "" {
+		q.Where("route", query.Like, params.Route)
 	}
-
-	conditionStr := condition.String()
-	if conditionStr != "" {
-		conditionStr = strings.TrimSuffix(condition.String(), "AND ")
+	if params.IsAuth != nil {
+		q.Where("is_auth", query.Eq, strconv.Itoa(int(*params.IsAuth)))
 	}
+	q.DefaultOrder(query.Order{Column: "sort"}, query.Order{Column: "id", Desc: true})
+	q.Page, q.PerPage, q.Cursor = params.Page, params.PerPage, params.Cursor
 
-	collection := model.NewPermission().ListPage(permission.Page, permission.PerPage, conditionStr, args)
-	return collection.ToCollection()
+	rows, result, err := model.NewPermission().ListPage(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	collection := resources.MapCollection[*model.Permission, *resources.PermissionResources](resources.NewPaginate(result), rows)
+	return collection.Only(query.SplitList(params.Fields)...), nil
 }
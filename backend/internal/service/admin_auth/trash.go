@@ -0,0 +1,132 @@
+package admin_auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	d "insight/data"
+	"insight/internal/model"
+	e "insight/internal/pkg/errors"
+	"insight/internal/pkg/query"
+	"insight/internal/pkg/utils"
+	"insight/internal/resources"
+	"insight/internal/service"
+	"insight/internal/validator/form"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// TrashService 回收站服务
+type TrashService struct {
+	service.Base
+	users *AdminUserService
+}
+
+func NewTrashService(logger *zap.Logger, users *AdminUserService) *TrashService {
+	return &TrashService{Base: service.NewBase(logger), users: users}
+}
+
+func (s *TrashService) trash(resource string) (model.Trash, error) {
+	trash, ok := model.GetTrash(resource)
+	if !ok {
+		return nil, e.NewBusinessError(e.NotFound, fmt.Sprintf("资源 %s 不支持回收站", resource))
+	}
+	return trash, nil
+}
+
+// List 回收站列表，默认按删除时间倒序
+func (s *TrashService) List(ctx context.Context, resource string, params *form.ListQuery) (*resources.Collection[*resources.TrashResources], error) {
+	trash, err := s.trash(resource)
+	if err != nil {
+		return nil, err
+	}
+	q, err := query.Parse(model.TrashQuery, params.Filter, params.Sort)
+	if err != nil {
+		return nil, e.NewBusinessError(e.InvalidParameter, err.Error())
+	}
+	q.DefaultOrder(query.Order{Column: "deleted_at", Desc: true}, query.Order{Column: "id", Desc: true})
+	q.Page, q.PerPage, q.Cursor = params.Page, params.PerPage, params.Cursor
+
+	rows, result, err := trash.List(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	data := make([]*resources.TrashResources, len(rows))
+	for i, row := range rows {
+		data[i] = &resources.TrashResources{Record: resources.ToResource(row)}
+		if d, ok := row.(interface{ DeletedTime() time.Time }); ok {
+			data[i].DeletedAt = utils.FormatDate{Time: d.DeletedTime()}
+		}
+	}
+	return resources.NewCollection(resources.NewPaginate(result), data).Only(query.SplitList(params.Fields)...), nil
+}
+
+// Restore 恢复记录，任一记录失败时全部回滚
+func (s *TrashService) Restore(ctx context.Context, resource string, ids []uint) error {
+	trash, err := s.trash(resource)
+	if err != nil {
+		return err
+	}
+	err = d.Transaction(ctx, func(ctx context.Context) error {
+		for _, id := range ids {
+			err := trash.Restore(ctx, id)
+			switch {
+			case errors.Is(err, gorm.ErrRecordNotFound):
+				return e.NewBusinessError(e.NotFound, fmt.Sprintf("ID 为 %d 的记录不在回收站中", id))
+			case errors.Is(err, gorm.ErrDuplicatedKey):
+				return e.NewBusinessError(e.RecordExists, fmt.Sprintf("ID 为 %d 的记录与现有记录的唯一字段冲突，请先修改或删除现有记录", id))
+			case err != nil:
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// 恢复的用户需要重新通过鉴权
+	if resource == "admin_users" {
+		for _, id := range ids {
+			_ = s.users.Invalidate(ctx, id)
+		}
+	}
+	return nil
+}
+
+// Purge 永久删除回收站中的记录
+func (s *TrashService) Purge(ctx context.Context, resource string, ids []uint) (int64, error) {
+	trash, err := s.trash(resource)
+	if err != nil {
+		return 0, err
+	}
+	count, err := trash.Purge(ctx, ids)
+	if err != nil {
+		return 0, err
+	}
+	s.Logger(ctx).Info("Trash records purged", zap.String("resource", resource), zap.Uints("ids", ids), zap.Int64("count", count))
+	return count, nil
+}
+
+// PurgeBefore 永久删除所有资源中在 before 之前删除的记录，返回各资源删除的数量
+func (s *TrashService) PurgeBefore(ctx context.Context, before time.Time, resourceNames ...string) (map[string]int64, error) {
+	if len(resourceNames) == 0 {
+		resourceNames = model.TrashResources()
+	}
+	purged := make(map[string]int64, len(resourceNames))
+	for _, resource := range resourceNames {
+		trash, err := s.trash(resource)
+		if err != nil {
+			return purged, err
+		}
+		// 分批删除，出错时已删除的批次同样计入
+		count, err := trash.PurgeBefore(ctx, before)
+		purged[resource] = count
+		if err != nil {
+			return purged, fmt.Errorf("purge %s: %w", resource, err)
+		}
+	}
+	return purged, nil
+}
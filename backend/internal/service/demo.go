@@ -1,24 +1,26 @@
 package service
 
 import (
+	"context"
 	"fmt"
-	log "insight/internal/pkg/logger"
 
 	"go.uber.org/zap"
 )
 
 type DemoService interface {
-	Demo(path string) (string, error)
+	Demo(ctx context.Context, path string) (string, error)
 }
 
-type demoServiceImpl struct{}
+type demoServiceImpl struct {
+	Base
+}
 
-func NewDemoService() DemoService {
-	return &demoServiceImpl{}
+func NewDemoService(logger *zap.Logger) DemoService {
+	return &demoServiceImpl{Base: NewBase(logger)}
 }
 
-func (s *demoServiceImpl) Demo(path string) (string, error) {
-	log.Logger.Info("DemoService processed path",
+func (s *demoServiceImpl) Demo(ctx context.Context, path string) (string, error) {
+	s.Logger(ctx).Info("DemoService processed path",
 		zap.String("path", path),
 	)
 	return fmt.Sprintf("path is %s", path), nil
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"insight/data"
+	"insight/internal/model"
+	"insight/internal/pkg/clock"
+	"insight/internal/pkg/deprecation"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// DeprecatedRouteReport 弃用路由及统计周期内的访问量
+type DeprecatedRouteReport struct {
+	deprecation.Route
+	Hits     int64
+	LastSeen time.Time // 最近一次写入访问量的时间，零值表示统计周期内没有访问
+}
+
+type RouteHitService struct {
+	Base
+	clock clock.Clock
+}
+
+func NewRouteHitService(logger *zap.Logger, clk clock.Clock) *RouteHitService {
+	return &RouteHitService{Base: NewBase(logger), clock: clk}
+}
+
+// Save 累加弃用路由的访问量，用作 deprecation.Counter.Flush 的写入函数
+func (s *RouteHitService) Save(ctx context.Context, counts map[deprecation.Hit]int64) error {
+	return data.Transaction(ctx, func(ctx context.Context) error {
+		for hit, n := range counts {
+			if err := model.NewRouteHit().Increment(ctx, hit.Day, hit.Method, hit.Path, n); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Report 返回全部已注册的弃用路由及最近 days 天(含今天)的访问量
+func (s *RouteHitService) Report(ctx context.Context, days int) ([]DeprecatedRouteReport, error) {
+	since := s.clock.Now().AddDate(0, 0, 1-days).Format(deprecation.DayLayout)
+	hits, err := model.NewRouteHit().Since(ctx, since)
+	if err != nil {
+		return nil, err
+	}
+
+	routes := deprecation.Routes()
+	reports := make([]DeprecatedRouteReport, len(routes))
+	index := make(map[string]*DeprecatedRouteReport, len(routes))
+	for i, route := range routes {
+		reports[i].Route = route
+		index[route.Method+" "+route.Path] = &reports[i]
+	}
+	// 已删除的路由不再统计
+	for _, hit := range hits {
+		if report, ok := index[hit.Method+" "+hit.Route]; ok {
+			report.Hits += hit.Hits
+			if hit.UpdatedAt.After(report.LastSeen) {
+				report.LastSeen = hit.UpdatedAt
+			}
+		}
+	}
+	return reports, nil
+}
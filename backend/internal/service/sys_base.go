@@ -1,5 +1,23 @@
 package service
 
+import (
+	"context"
+	log "insight/internal/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
 type Base struct {
-	aUid *uint
+	aUid   *uint
+	logger *zap.Logger
+}
+
+// NewBase 创建服务基础结构，logger 为 nil 时使用全局日志对象
+func NewBase(logger *zap.Logger) Base {
+	return Base{logger: logger}
+}
+
+// Logger 返回请求级日志对象，自动带上 request_id、user_id 与链路信息，不在请求中时使用注入的日志对象
+func (b *Base) Logger(ctx context.Context) *zap.Logger {
+	return log.WithContextOr(ctx, b.logger)
 }
@@ -0,0 +1,188 @@
+// Package testutil 控制器测试工具：启动完整路由，使用独立的 SQLite 内存库、内存缓存与假时钟，
+// 提供种子数据、令牌签发与对统一响应结构的断言。
+//
+// 配置通过 config.SetConfig 设置为全局配置，使用 Harness 的测试不能并行执行。
+package testutil
+
+import (
+	"context"
+	"fmt"
+	"insight/config"
+	"insight/config/autoload"
+	"insight/data/dbtest"
+	_ "insight/data/migrations"
+	"insight/data/migrator"
+	"insight/data/seeder"
+	"insight/data/seeds"
+	"insight/internal/container"
+	"insight/internal/model"
+	"insight/internal/pkg/cache"
+	"insight/internal/pkg/clock"
+	log "insight/internal/pkg/logger"
+	"insight/internal/pkg/utils/token"
+	"insight/internal/routers"
+	"insight/internal/validator"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// StartTime 假时钟的初始时间
+var StartTime = time.Date(2026, 1, 1, 8, 0, 0, 0, time.UTC)
+
+// TestPassword 通过 TokenWithRoles 创建的用户的密码
+const TestPassword = "password123"
+
+// Harness 一个测试用的完整应用
+type Harness struct {
+	t      testing.TB
+	Config *config.Config
+	DB     *gorm.DB
+	Cache  cache.Store
+	Clock  *clock.Fake
+	Deps   *container.Container
+	Engine *gin.Engine
+}
+
+type options struct {
+	configure []func(*config.Config)
+	seedSet   string
+	container []container.Option
+}
+
+type Option func(*options)
+
+// WithConfig 在默认测试配置上修改配置，如开启限流
+func WithConfig(fn func(cfg *config.Config)) Option {
+	return func(o *options) { o.configure = append(o.configure, fn) }
+}
+
+// WithSeedSet 加载 base 与 env 种子集，默认为 test；传入 seeder.BaseSet 时只加载 base
+func WithSeedSet(env string) Option {
+	return func(o *options) { o.seedSet = env }
+}
+
+// WithContainer 追加容器选项，如替换为假服务
+func WithContainer(opts ...container.Option) Option {
+	return func(o *options) { o.container = append(o.container, opts...) }
+}
+
+var databases atomic.Int64
+
+// Config 测试配置：内置默认值，SQLite 命名内存库，关闭限流、指标、链路追踪与访问日志
+func Config() *config.Config {
+	cfg := config.Defaults()
+	cfg.System.Env = "test"
+	cfg.System.Language = "zh_CN"
+	cfg.Database = autoload.DatabaseConfig{
+		Enable:   true,
+		Driver:   "sqlite",
+		Path:     fmt.Sprintf("file:testutil_%d?mode=memory", databases.Add(1)),
+		LogLevel: "silent",
+	}
+	// TTL 与 LoginService 一致按秒计
+	cfg.Jwt = autoload.JwtConfig{Secret: "testutil-secret", HeaderPrefix: "Bearer", TTL: 7200}
+	cfg.RateLimit.Enable = false
+	cfg.Metrics.Enable = false
+	cfg.Tracing.Enable = false
+	cfg.Logger.Access.Enable = false
+	return cfg
+}
+
+// New 创建测试应用：执行全部迁移并加载种子数据，测试结束时关闭数据库
+func New(t testing.TB, opts ...Option) *Harness {
+	t.Helper()
+	o := &options{seedSet: "test"}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	gin.SetMode(gin.TestMode)
+	if log.Logger == nil {
+		log.Logger = zap.NewNop()
+	}
+	validator.InitValidatorTrans("zh")
+
+	cfg := Config()
+	for _, fn := range o.configure {
+		fn(cfg)
+	}
+	config.SetConfig(cfg)
+
+	db := dbtest.OpenConfig(t, cfg.Database)
+	if _, err := migrator.New(db).Up(0); err != nil {
+		t.Fatalf("testutil: migrate: %v", err)
+	}
+
+	h := &Harness{
+		t:      t,
+		Config: cfg,
+		DB:     db,
+		Cache:  cache.NewMemory(0, 0),
+		Clock:  clock.NewFake(StartTime),
+	}
+	if o.seedSet != "" {
+		if _, err := seeder.New(db, seeds.FS).Seed(o.seedSet); err != nil {
+			t.Fatalf("testutil: seed %s: %v", o.seedSet, err)
+		}
+	}
+
+	h.Deps = container.New(append([]container.Option{
+		container.WithConfig(cfg),
+		container.WithLogger(log.Logger),
+		container.WithDB(db),
+		container.WithCache(h.Cache),
+		container.WithClock(h.Clock),
+	}, o.container...)...)
+	engine, err := routers.NewEngine(h.Deps)
+	if err != nil {
+		t.Fatalf("testutil: new engine: %v", err)
+	}
+	h.Engine = engine
+	return h
+}
+
+// Context 返回绑定测试数据库的上下文，测试中直接调用模型与服务时使用
+func (h *Harness) Context() context.Context {
+	return h.Deps.Context(context.Background())
+}
+
+// Seed 写入测试数据，已存在的数据按唯一标识更新
+func (h *Harness) Seed(fixture *seeder.Fixture) {
+	h.t.Helper()
+	if _, err := seeder.New(h.DB, seeds.FS).Apply(fixture); err != nil {
+		h.t.Fatalf("testutil: seed fixture: %v", err)
+	}
+}
+
+// Token 为已存在的用户签发访问令牌，过期时间按假时钟计算
+func (h *Harness) Token(username string) string {
+	h.t.Helper()
+	user := model.NewAdminUsers().GetUserInfo(h.Context(), username)
+	if user == nil {
+		h.t.Fatalf("testutil: user %q does not exist", username)
+	}
+	accessToken, err := token.Generate(h.Deps.LoginService().NewAdminCustomClaims(user))
+	if err != nil {
+		h.t.Fatalf("testutil: generate token: %v", err)
+	}
+	return accessToken
+}
+
+// TokenWithRoles 创建拥有指定角色的用户并签发访问令牌，角色相同时复用同一用户
+func (h *Harness) TokenWithRoles(roles ...string) string {
+	h.t.Helper()
+	username := "user_" + strings.Join(roles, "_")
+	h.Seed(&seeder.Fixture{AdminUsers: []seeder.AdminUserFixture{{
+		Username: username,
+		Password: TestPassword,
+		NickName: username,
+		Roles:    roles,
+	}}})
+	return h.Token(username)
+}
@@ -0,0 +1,76 @@
+package testutil
+
+import (
+	"insight/config"
+	"insight/data/seeder"
+	"insight/internal/model"
+	e "insight/internal/pkg/errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHarness(t *testing.T) {
+	h := New(t)
+
+	h.GET("/api/v1/hello").Do().OK().Field("result", "Hello, Gin!")
+	var health struct {
+		Status string `json:"status"`
+	}
+	h.GET("/healthz").Do().Status(http.StatusOK).JSON(&health)
+	assert.Equal(t, "up", health.Status)
+
+	// 测试种子集中的用户
+	h.GET("/api/v1/admin/users/info").As("admin").Do().OK().
+		Field("username", "admin").
+		Field("roles", []string{"super_admin"})
+	h.GET("/api/v1/admin/users/info").Do().Fail(e.NotLogin)
+}
+
+func TestHarnessIsolation(t *testing.T) {
+	first := New(t)
+	first.Seed(&seeder.Fixture{AdminUsers: []seeder.AdminUserFixture{{Username: "isolated", Password: TestPassword}}})
+	second := New(t, WithSeedSet(seeder.BaseSet))
+
+	assert.NotNil(t, model.NewAdminUsers().GetUserInfo(first.Context(), "isolated"))
+	assert.Nil(t, model.NewAdminUsers().GetUserInfo(second.Context(), "isolated"))
+	assert.Nil(t, model.NewAdminUsers().GetUserInfo(second.Context(), "admin"), "Base set should not load test users")
+}
+
+func TestTokenWithRoles(t *testing.T) {
+	h := New(t)
+	accessToken := h.TokenWithRoles("viewer")
+	assert.Equal(t, accessToken, h.TokenWithRoles("viewer"), "Same roles and clock should reuse the user")
+
+	h.GET("/api/v1/admin/users/info").Token(accessToken).Do().OK().
+		Field("username", "user_viewer").
+		Field("roles.0", "viewer")
+}
+
+func TestFakeClock(t *testing.T) {
+	h := New(t)
+	var result struct {
+		AccessToken string `json:"access_token"`
+		ExpiresAt   int64  `json:"expires_at"`
+	}
+	h.POST("/api/v1/admin/login/").JSON(map[string]string{"username": "admin", "password": "admin123"}).
+		Do().OK().Data(&result)
+	assert.Equal(t, StartTime.Unix()+int64(h.Config.Jwt.TTL), result.ExpiresAt)
+
+	h.GET("/api/v1/admin/users/info").Token(result.AccessToken).Do().OK()
+	h.Clock.Advance(h.Config.Jwt.TTL*time.Second + time.Minute)
+	h.GET("/api/v1/admin/users/info").Token(result.AccessToken).Do().Fail(e.NotLogin)
+}
+
+func TestWithConfig(t *testing.T) {
+	h := New(t, WithConfig(func(cfg *config.Config) { cfg.OpenAPI.Enable = false }))
+	assert.Same(t, h.Config, config.GetConfig())
+	assert.Equal(t, http.StatusNotFound, h.GET("/api/openapi.json").Do().Recorder.Code)
+
+	h = New(t)
+	require.True(t, h.Config.OpenAPI.Enable)
+	h.GET("/api/openapi.json").Do().Status(http.StatusOK)
+}
@@ -0,0 +1,85 @@
+package testutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+)
+
+// Request 待发送的测试请求
+type Request struct {
+	h      *Harness
+	method string
+	path   string
+	query  url.Values
+	header http.Header
+	body   []byte
+}
+
+func (h *Harness) Request(method, path string) *Request {
+	return &Request{h: h, method: method, path: path, query: url.Values{}, header: http.Header{}}
+}
+
+func (h *Harness) GET(path string) *Request { return h.Request(http.MethodGet, path) }
+
+func (h *Harness) POST(path string) *Request { return h.Request(http.MethodPost, path) }
+
+func (h *Harness) PUT(path string) *Request { return h.Request(http.MethodPut, path) }
+
+func (h *Harness) DELETE(path string) *Request { return h.Request(http.MethodDelete, path) }
+
+// Query 追加查询参数
+func (r *Request) Query(key, value string) *Request {
+	r.query.Add(key, value)
+	return r
+}
+
+func (r *Request) Header(key, value string) *Request {
+	r.header.Set(key, value)
+	return r
+}
+
+// JSON 以 JSON 编码 body 作为请求体
+func (r *Request) JSON(body any) *Request {
+	r.h.t.Helper()
+	b, err := json.Marshal(body)
+	if err != nil {
+		r.h.t.Fatalf("testutil: encode request body: %v", err)
+	}
+	r.body = b
+	return r.Header("Content-Type", "application/json")
+}
+
+// Token 使用访问令牌认证
+func (r *Request) Token(accessToken string) *Request {
+	return r.Header("Authorization", r.h.Config.Jwt.HeaderPrefix+" "+accessToken)
+}
+
+// As 以已存在的用户身份认证
+func (r *Request) As(username string) *Request {
+	r.h.t.Helper()
+	return r.Token(r.h.Token(username))
+}
+
+// Do 通过完整路由处理请求
+func (r *Request) Do() *Response {
+	r.h.t.Helper()
+	target := r.path
+	if len(r.query) > 0 {
+		target += "?" + r.query.Encode()
+	}
+	var body io.Reader
+	if r.body != nil {
+		body = bytes.NewReader(r.body)
+	}
+	req := httptest.NewRequest(r.method, target, body)
+	for key, values := range r.header {
+		req.Header[key] = values
+	}
+	recorder := httptest.NewRecorder()
+	r.h.Engine.ServeHTTP(recorder, req)
+	return &Response{t: r.h.t, Recorder: recorder}
+}
@@ -0,0 +1,143 @@
+package testutil
+
+import (
+	"encoding/json"
+	e "insight/internal/pkg/errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Envelope 统一响应结构 response.Result，data 保留原始 JSON
+type Envelope struct {
+	Code      int             `json:"code"`
+	Msg       string          `json:"msg"`
+	Data      json.RawMessage `json:"data"`
+	Cost      string          `json:"cost"`
+	RequestID string          `json:"request_id"`
+}
+
+// Response 测试请求的响应，断言方法失败时标记测试失败并继续执行，可链式调用
+type Response struct {
+	t        testing.TB
+	Recorder *httptest.ResponseRecorder
+	envelope *Envelope
+}
+
+// Envelope 按统一响应结构解析响应体，响应体不是统一响应结构时测试立即失败
+func (r *Response) Envelope() *Envelope {
+	r.t.Helper()
+	if r.envelope == nil {
+		envelope := &Envelope{}
+		if err := json.Unmarshal(r.Recorder.Body.Bytes(), envelope); err != nil {
+			r.t.Fatalf("testutil: response is not a result envelope: %v\n%s", err, r.Recorder.Body.String())
+		}
+		r.envelope = envelope
+	}
+	return r.envelope
+}
+
+// Status 断言 HTTP 状态码
+func (r *Response) Status(status int) *Response {
+	r.t.Helper()
+	assert.Equal(r.t, status, r.Recorder.Code, "HTTP status, body: %s", r.Recorder.Body.String())
+	return r
+}
+
+// Code 断言业务码
+func (r *Response) Code(code int) *Response {
+	r.t.Helper()
+	assert.Equal(r.t, code, r.Envelope().Code, "Business code, body: %s", r.Recorder.Body.String())
+	return r
+}
+
+// OK 断言 HTTP 200 且业务成功
+func (r *Response) OK() *Response {
+	r.t.Helper()
+	return r.Status(http.StatusOK).Code(e.SUCCESS)
+}
+
+// Fail 断言 HTTP 200 且业务码为 code
+func (r *Response) Fail(code int) *Response {
+	r.t.Helper()
+	return r.Status(http.StatusOK).Code(code)
+}
+
+// Message 断言提示信息
+func (r *Response) Message(msg string) *Response {
+	r.t.Helper()
+	assert.Equal(r.t, msg, r.Envelope().Msg, "Message, body: %s", r.Recorder.Body.String())
+	return r
+}
+
+// Header 断言响应头
+func (r *Response) Header(key, value string) *Response {
+	r.t.Helper()
+	assert.Equal(r.t, value, r.Recorder.Header().Get(key), "Header %s", key)
+	return r
+}
+
+// Data 将 data 解析到 v
+func (r *Response) Data(v any) *Response {
+	r.t.Helper()
+	if err := json.Unmarshal(r.Envelope().Data, v); err != nil {
+		r.t.Fatalf("testutil: decode data: %v\n%s", err, r.Recorder.Body.String())
+	}
+	return r
+}
+
+// JSON 将整个响应体解析到 v，用于不使用统一响应结构的接口
+func (r *Response) JSON(v any) *Response {
+	r.t.Helper()
+	if err := json.Unmarshal(r.Recorder.Body.Bytes(), v); err != nil {
+		r.t.Fatalf("testutil: decode body: %v\n%s", err, r.Recorder.Body.String())
+	}
+	return r
+}
+
+// Field 断言 data 中 path 处的值，path 以 . 分隔，数组使用下标，如 "Data.0.username"；
+// expected 按 JSON 编码后比较，数字类型不必与解析结果一致
+func (r *Response) Field(path string, expected any) *Response {
+	r.t.Helper()
+	var value any
+	r.Data(&value)
+	for _, key := range strings.Split(path, ".") {
+		switch node := value.(type) {
+		case map[string]any:
+			v, ok := node[key]
+			if !ok {
+				assert.Fail(r.t, "Field not found", "%s in %s", path, r.Recorder.Body.String())
+				return r
+			}
+			value = v
+		case []any:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(node) {
+				assert.Fail(r.t, "Index out of range", "%s in %s", path, r.Recorder.Body.String())
+				return r
+			}
+			value = node[i]
+		default:
+			assert.Fail(r.t, "Field not found", "%s in %s", path, r.Recorder.Body.String())
+			return r
+		}
+	}
+	assert.Equal(r.t, normalize(r.t, expected), value, "Field %s", path)
+	return r
+}
+
+// normalize 将 v 转换为 JSON 解析后的表示，便于与解析结果比较
+func normalize(t testing.TB, v any) any {
+	t.Helper()
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("testutil: encode expected value: %v", err)
+	}
+	var out any
+	_ = json.Unmarshal(b, &out)
+	return out
+}
@@ -7,3 +7,17 @@ type AddAdminUserForm struct {
 	Email    string `form:"email" json:"email" binding:"omitempty,email"`
 	IsAdmin  int8   `form:"is_admin" json:"is_admin" binding:"omitempty,oneof=0 1"`
 }
+
+type EditAdminUserForm struct {
+	ID       uint   `form:"id" json:"id" binding:"required"`
+	Version  uint   `form:"version" json:"version" binding:"required"` // 版本号，也可以通过 If-Match 请求头传入
+	NickName string `form:"nickname" json:"nickname" binding:"omitempty,max=64"`
+	Mobile   string `form:"mobile" json:"mobile" binding:"omitempty,mobile"`
+	Email    string `form:"email" json:"email" binding:"omitempty,email"`
+	Avatar   string `form:"avatar" json:"avatar" binding:"omitempty,max=255"`
+	Status   *int8  `form:"status" json:"status" binding:"omitempty,oneof=0 1"`
+}
+
+func NewEditAdminUserForm() *EditAdminUserForm {
+	return &EditAdminUserForm{}
+}
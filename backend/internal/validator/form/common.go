@@ -5,8 +5,32 @@ type Paginate struct {
 	PerPage int `form:"per_page" json:"per_page" binding:"omitempty,gt=0"` // 必填。每页数量>=1
 }
 
+// ListQuery 列表查询参数
+// filter=字段:操作符:值 可重复，sort=-字段,字段，传入 cursor 时使用游标分页，第一页传空值
+// fields=字段,字段 只返回指定字段，include=关联,关联 返回关联资源
+type ListQuery struct {
+	Paginate
+	Filter  []string `form:"filter" json:"filter" binding:"omitempty,max=20"`
+	Sort    string   `form:"sort" json:"sort" binding:"omitempty,max=255"`
+	Cursor  *string  `form:"cursor" json:"cursor" binding:"omitempty"`
+	Fields  string   `form:"fields" json:"fields" binding:"omitempty,max=255"`
+	Include string   `form:"include" json:"include" binding:"omitempty,max=255"`
+}
+
+func NewListQuery() *ListQuery {
+	return &ListQuery{}
+}
+
+type IDs struct {
+	IDs []uint `form:"ids" json:"ids" binding:"required,min=1,max=100"`
+}
+
+func NewIDsForm() *IDs {
+	return &IDs{}
+}
+
 type ID struct {
-	ID uint `form:"id" json:"id" binding:"required"`
+	ID uint `form:"id" uri:"id" json:"id" binding:"required"`
 }
 
 func NewIDForm() *ID {